@@ -0,0 +1,236 @@
+package odds
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+/*
+Binary snapshot layout. All lengths and counts are unsigned varints.
+
+	magic    "ODDS"
+	version  1 byte
+	name     name length, registered type name
+	entries  any number of (1, weight length, weight magnitude, data length, data)
+	end      0
+	count    number of entries
+	total    total length, total magnitude
+	checksum CRC-32 (IEEE) of everything above, 4 bytes big endian
+
+Since the count comes after the entries, snapshots can be written in a single
+pass without knowing how many entries there will be.
+*/
+const (
+	binaryMagic   = "ODDS"
+	binaryVersion = 1
+
+	// Written before every entry and after the last one
+	binaryEntryMarker = 1
+	binaryEndMarker   = 0
+
+	// Upper bound on any single length read from a snapshot. Protects against
+	// huge allocations when reading a corrupted file.
+	maxBinaryLength = 1 << 30
+)
+
+var ErrCorruptSnapshot = errors.New("odds: corrupt binary snapshot")
+
+////////////
+// WRITER //
+////////////
+
+type binaryWriter struct {
+	w        io.Writer
+	checksum hash.Hash32
+	n        int64
+	buf      [binary.MaxVarintLen64]byte
+}
+
+func newBinaryWriter(w io.Writer) *binaryWriter {
+	return &binaryWriter{w: w, checksum: crc32.NewIEEE()}
+}
+
+func (bw *binaryWriter) write(b []byte) error {
+	n, err := bw.w.Write(b)
+	bw.n += int64(n)
+	bw.checksum.Write(b[:n])
+	return err
+}
+
+func (bw *binaryWriter) writeUvarint(x uint64) error {
+	return bw.write(bw.buf[:binary.PutUvarint(bw.buf[:], x)])
+}
+
+func (bw *binaryWriter) writeBytes(b []byte) error {
+	if err := bw.writeUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+	return bw.write(b)
+}
+
+func (bw *binaryWriter) writeChecksum() error {
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], bw.checksum.Sum32())
+	n, err := bw.w.Write(sum[:])
+	bw.n += int64(n)
+	return err
+}
+
+////////////
+// READER //
+////////////
+
+type binaryReader struct {
+	r        io.ByteReader
+	checksum hash.Hash32
+	n        int64
+}
+
+func newBinaryReader(r io.Reader) *binaryReader {
+	byteReader, ok := r.(io.ByteReader)
+	if !ok {
+		byteReader = bufio.NewReader(r)
+	}
+	return &binaryReader{r: byteReader, checksum: crc32.NewIEEE()}
+}
+
+func (br *binaryReader) ReadByte() (byte, error) {
+	b, err := br.r.ReadByte()
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	br.n++
+	br.checksum.Write([]byte{b})
+	return b, nil
+}
+
+func (br *binaryReader) read(b []byte) error {
+	for i := range b {
+		c, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		b[i] = c
+	}
+	return nil
+}
+
+func (br *binaryReader) readUvarint() (uint64, error) {
+	x, err := binary.ReadUvarint(br)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return 0, fmt.Errorf("%w: %v", ErrCorruptSnapshot, err)
+	}
+	return x, err
+}
+
+func (br *binaryReader) readBytes() ([]byte, error) {
+	length, err := br.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if length > maxBinaryLength {
+		return nil, fmt.Errorf("%w: length %d too large", ErrCorruptSnapshot, length)
+	}
+	b := make([]byte, length)
+	return b, br.read(b)
+}
+
+func (br *binaryReader) readChecksum() error {
+	expected := br.checksum.Sum32()
+	var sum [4]byte
+	for i := range sum {
+		b, err := br.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		br.n++
+		sum[i] = b
+	}
+	if binary.BigEndian.Uint32(sum[:]) != expected {
+		return fmt.Errorf("%w: checksum mismatch", ErrCorruptSnapshot)
+	}
+	return nil
+}
+
+// A snapshot never ends cleanly in the middle, so any EOF is unexpected
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+//////////////////
+// ODDS METHODS //
+//////////////////
+
+/*
+Writes "o" to "w" as a compact binary snapshot. Every entry.Data is encoded
+with o.EncodeFunction. Entries are streamed straight from o.Map through an
+Encoder, so no intermediate copy of the odds is made. Implements io.WriterTo.
+*/
+func (o *Odds[D, H]) WriteTo(w io.Writer) (int64, error) {
+	encoder, err := NewEncoder(w, o)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range o.Map {
+		if err := encoder.Encode(entry); err != nil {
+			return encoder.BytesWritten(), err
+		}
+	}
+
+	err = encoder.Close()
+	return encoder.BytesWritten(), err
+}
+
+/*
+Reads a binary snapshot written by WriteTo into "o", replacing any existing
+entries. If "o" has no DecodeFunction, all of its functions are taken from the
+options registered under the type name stored in the snapshot (see Register).
+Truncated or corrupted snapshots, including ones whose total does not match the
+sum of their weights, result in an error and leave "o" untouched. Implements
+io.ReaderFrom.
+
+If "r" is not an io.ByteReader it is buffered, so bytes past the end of the
+snapshot may be consumed from "r".
+*/
+func (o *Odds[D, H]) ReadFrom(r io.Reader) (int64, error) {
+	decoder, err := NewDecoder(r, o)
+	if err != nil {
+		return 0, err
+	}
+
+	// Decode into a fresh odds object so a failure never leaves "o" half loaded
+	var result *Odds[D, H]
+	if decoder.options != nil {
+		result = decoder.options.Odds()
+	} else {
+		result = NewOddsFromReference(o)
+	}
+
+	for {
+		entry, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return decoder.BytesRead(), err
+		}
+		result.AddEntry(entry)
+	}
+
+	if decoder.options != nil {
+		decoder.options.applyTo(o)
+	}
+	o.Map = result.Map
+	o.Total = result.Total
+
+	return decoder.BytesRead(), nil
+}
@@ -0,0 +1,96 @@
+package odds
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+/////////////////////
+// CDF DEFINITIONS //
+/////////////////////
+
+// An entry along with the exact probability of it or anything before it
+type CDFEntry[D any, H comparable] struct {
+	Entry      *Entry[D, H]
+	Cumulative *big.Rat
+}
+
+/*
+Cumulative distribution table of an odds object, sorted by an ordering of the
+outcome space. The table is a snapshot, so later changes to the odds are not
+reflected in it.
+*/
+type CDF[D any, H comparable] struct {
+	Entries []*CDFEntry[D, H]
+
+	// Cumulative weights, which are what the lookups search through
+	cumulative []*big.Int
+	total      *big.Int
+}
+
+/*
+Build the cumulative distribution table of "o", with entries sorted so that
+"less" holds between earlier and later data.
+*/
+func (o *Odds[D, H]) CDF(less func(D, D) bool) *CDF[D, H] {
+	entries := o.Entries()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return less(entries[i].Data, entries[j].Data)
+	})
+
+	cdf := &CDF[D, H]{
+		Entries:    make([]*CDFEntry[D, H], len(entries)),
+		cumulative: make([]*big.Int, len(entries)),
+		total:      new(big.Int).Set(o.Total),
+	}
+
+	running := big.NewInt(0)
+	for i, entry := range entries {
+		running.Add(running, entry.Weight)
+		cdf.cumulative[i] = new(big.Int).Set(running)
+		cdf.Entries[i] = &CDFEntry[D, H]{entry, o.WeightAsProbability(running)}
+	}
+
+	return cdf
+}
+
+/////////////
+// LOOKUPS //
+/////////////
+
+/*
+Returns the first entry whose cumulative probability is at least "p", found by
+binary search. "p" must be between 0 and 1.
+*/
+func (cdf *CDF[D, H]) Quantile(p *big.Rat) (*Entry[D, H], error) {
+	if p.Sign() < 0 || p.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, fmt.Errorf("odds: quantile %s is not between 0 and 1", p.RatString())
+	}
+	if cdf.total.Sign() == 0 {
+		return nil, ErrZeroTotal
+	}
+
+	// cumulative / total >= num / denom, without leaving the integers
+	threshold := new(big.Int).Mul(p.Num(), cdf.total)
+	scaled := new(big.Int)
+	i := sort.Search(len(cdf.cumulative), func(i int) bool {
+		return scaled.Mul(cdf.cumulative[i], p.Denom()).Cmp(threshold) >= 0
+	})
+
+	return cdf.Entries[i].Entry, nil
+}
+
+// Returns the entry at the 50% quantile
+func (cdf *CDF[D, H]) Median() (*Entry[D, H], error) {
+	return cdf.Quantile(big.NewRat(1, 2))
+}
+
+// Returns the entry at the given percentile, from 0 to 100
+func (cdf *CDF[D, H]) Percentile(percent float64) (*Entry[D, H], error) {
+	p := new(big.Rat).SetFloat64(percent)
+	if p == nil {
+		return nil, fmt.Errorf("odds: invalid percentile %v", percent)
+	}
+	return cdf.Quantile(p.Quo(p, big.NewRat(100, 1)))
+}
@@ -1,6 +1,7 @@
 package odds
 
 import (
+	"fmt"
 	"math/big"
 )
 
@@ -85,6 +86,75 @@ func (o *Odds[D, H]) Merge_CombineInPlace(objects ...*Odds[D, H]) *Odds[D, H] {
 	return o
 }
 
+/////////////
+// MIXTURE //
+/////////////
+
+/*
+Create the mixture of all the components, where each component takes up its
+given share of the total weight. Unlike chaining AddOdds, a single scale factor
+is computed for each component from the least common multiple of the reduced
+ratios weight / component.Total, so intermediate weights stay small. Components
+with a weight of 0 are left out. Neither the components nor the weights are
+modified, though the data of the components is shared with the result.
+*/
+func Mixture[D any, H comparable](components []*Odds[D, H], weights []*big.Int) (*Odds[D, H], error) {
+	if len(components) != len(weights) {
+		return nil, fmt.Errorf("odds: %d components but %d weights", len(components), len(weights))
+	}
+
+	/*
+		The share of each entry in component i is weight_i / total_i. Bring all
+		those ratios to a common denominator to get integer scale factors.
+	*/
+	ratios := make([]*big.Rat, len(components))
+	lcm := big.NewInt(1)
+	var reference *Odds[D, H]
+	for i, component := range components {
+		if weights[i].Sign() < 0 {
+			return nil, fmt.Errorf("odds: negative mixture weight %s", weights[i])
+		}
+		if weights[i].Sign() == 0 {
+			continue
+		}
+		if component.Total.Sign() == 0 {
+			return nil, fmt.Errorf("odds: mixture component %d has no weight", i)
+		}
+
+		ratios[i] = new(big.Rat).SetFrac(weights[i], component.Total)
+		gcd := new(big.Int).GCD(nil, nil, lcm, ratios[i].Denom())
+		lcm.Mul(lcm, new(big.Int).Quo(ratios[i].Denom(), gcd))
+
+		if reference == nil {
+			reference = component
+		}
+	}
+
+	if reference == nil {
+		return nil, fmt.Errorf("odds: mixture has no components with weight")
+	}
+
+	mixture := NewOddsFromReference(reference)
+	for i, component := range components {
+		if ratios[i] == nil {
+			continue
+		}
+
+		scaleFactor := new(big.Int).Quo(lcm, ratios[i].Denom())
+		scaleFactor.Mul(scaleFactor, ratios[i].Num())
+
+		for _, entry := range component.Map {
+			mixture.AddEntry(&Entry[D, H]{
+				entry.Hash,
+				entry.Data,
+				new(big.Int).Mul(entry.Weight, scaleFactor),
+			})
+		}
+	}
+
+	return mixture.Reduce(), nil
+}
+
 //////////////
 // CONVOLVE //
 //////////////
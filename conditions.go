@@ -1,6 +1,12 @@
 package odds
 
-import "math/big"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrZeroProbability = errors.New("odds: event has zero probability")
 
 /*
 For every entry in "o", check if it satisties the "removalCondition" function.
@@ -40,6 +46,86 @@ func (o *Odds[D, H]) SplitByConditions(
 	return oddsArray
 }
 
+/*
+Partition "o" by the key of each entry without modifying "o". Returns the
+conditional distribution of each group, reduced, along with the exact weight of
+each group relative to o.Total. The entry data is shared with "o". The groups
+can be put back together with Ungroup.
+*/
+func GroupBy[D any, H comparable, K comparable](
+	o *Odds[D, H],
+	key func(*Entry[D, H]) K,
+) (map[K]*Odds[D, H], map[K]*big.Int) {
+
+	groups := map[K]*Odds[D, H]{}
+	for _, entry := range o.Map {
+		k := key(entry)
+		group, exists := groups[k]
+		if !exists {
+			group = NewOddsFromReference(o)
+			groups[k] = group
+		}
+		group.AddEntry(entry.clone())
+	}
+
+	weights := map[K]*big.Int{}
+	for k, group := range groups {
+		weights[k] = new(big.Int).Set(group.Total)
+		group.Reduce()
+	}
+
+	return groups, weights
+}
+
+/*
+Reassemble groups produced by GroupBy into a single odds object, where each
+group takes up its given share of the weight. This is the Mixture of the
+groups. Groups with a weight of 0 are left out. Neither the groups nor the
+weights are modified.
+*/
+func Ungroup[D any, H comparable, K comparable](
+	groups map[K]*Odds[D, H],
+	weights map[K]*big.Int,
+) (*Odds[D, H], error) {
+
+	components := make([]*Odds[D, H], 0, len(groups))
+	componentWeights := make([]*big.Int, 0, len(groups))
+	for k, group := range groups {
+		weight, exists := weights[k]
+		if !exists {
+			return nil, fmt.Errorf("odds: no weight for group %v", k)
+		}
+		components = append(components, group)
+		componentWeights = append(componentWeights, weight)
+	}
+
+	return Mixture(components, componentWeights)
+}
+
+/*
+Returns a new odds object holding only the entries of "o" which satisfy the
+condition, renormalized so it is the distribution of "o" given the event, along
+with the exact probability of the event. "o" is not modified, and the entry data
+is shared with "o". Returns ErrZeroProbability if no weight satisfies the
+condition.
+*/
+func (o *Odds[D, H]) Condition(condition func(*Entry[D, H]) bool) (*Odds[D, H], *big.Rat, error) {
+	conditioned := NewOddsFromReference(o)
+
+	for _, entry := range o.Map {
+		if condition(entry) {
+			conditioned.AddEntry(entry.clone())
+		}
+	}
+
+	if conditioned.Total.Sign() == 0 {
+		return nil, nil, ErrZeroProbability
+	}
+
+	probability := o.WeightAsProbability(conditioned.Total)
+	return conditioned.Reduce(), probability, nil
+}
+
 /*
 Returns the weight of entries in "o" which satisfy the given condition.
 */
@@ -0,0 +1,101 @@
+package odds
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var csvHeader = []string{"outcome", "weight", "probability", "percent"}
+
+/*
+Writes "o" to "w" as CSV with one row per entry, ordered by weight. The columns
+are the outcome, the raw weight, the probability as an exact reduced fraction,
+and the probability as a decimal percent. Each outcome is rendered with
+"formatter", or with o.DisplayFunction if "formatter" is nil.
+*/
+func (o *Odds[D, H]) ExportCSV(w io.Writer, formatter func(D) string) error {
+	if formatter == nil {
+		formatter = o.DisplayFunction
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	if o.Total.Sign() != 0 {
+		for _, entry := range o.EntriesByWeight() {
+			probability := new(big.Rat).SetFrac(entry.Weight, o.Total)
+			err := writer.Write([]string{
+				formatter(entry.Data),
+				entry.Weight.String(),
+				probability.RatString(),
+				o.WeightAsPercent(entry.Weight).Text('f', 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+/*
+Reads CSV rows from "r" and adds each of them to "o". The first row is treated
+as a header and skipped. Only the first two columns are used: the outcome,
+which is turned into data with "parser", and its integer weight. Any further
+columns, such as the probabilities written by ExportCSV, are ignored, so hand
+made frequency tables with just two columns can be imported as well. Every row
+is parsed before any is added, so on an error "o" is left untouched.
+*/
+func (o *Odds[D, H]) ImportCSV(r io.Reader, parser func(string) (D, error)) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	if _, err := reader.Read(); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	type row struct {
+		data   D
+		weight *big.Int
+	}
+	rows := []row{}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < 2 {
+			return fmt.Errorf("odds: csv row %d has %d columns, need at least 2", line, len(record))
+		}
+
+		weight, ok := new(big.Int).SetString(record[1], 10)
+		if !ok || weight.Sign() < 0 {
+			return fmt.Errorf("odds: csv row %d has invalid weight %q", line, record[1])
+		}
+
+		data, err := parser(record[0])
+		if err != nil {
+			return fmt.Errorf("odds: csv row %d: %w", line, err)
+		}
+
+		rows = append(rows, row{data, weight})
+	}
+
+	for _, row := range rows {
+		o.Add(row.data, row.weight)
+	}
+	return nil
+}
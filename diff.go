@@ -0,0 +1,152 @@
+package odds
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Types of changes an entry can go through between two odds objects
+type DiffKind int
+
+const (
+	Diff_Added DiffKind = 1 << iota
+	Diff_Removed
+	Diff_Reweighted
+)
+
+/*
+A single entry which differs between two odds objects. Before and After are the
+exact probabilities of the entry in each of them.
+*/
+type DiffEntry[D any, H comparable] struct {
+	Kind   DiffKind
+	Hash   H
+	Data   D
+	Before *big.Rat
+	After  *big.Rat
+}
+
+// Change in probability from Before to After
+func (d *DiffEntry[D, H]) Change() *big.Rat {
+	return new(big.Rat).Sub(d.After, d.Before)
+}
+
+/*
+All the entries which differ between two odds objects, each sorted by the
+absolute change in their probability, largest first.
+*/
+type DiffReport[D any, H comparable] struct {
+	Added      []*DiffEntry[D, H]
+	Removed    []*DiffEntry[D, H]
+	Reweighted []*DiffEntry[D, H]
+
+	displayFunction func(D) string
+}
+
+/*
+Compare the normalized probabilities of "a" and "b", matching entries by their
+hash. Raw weights are never compared, so odds which only differ by Scale or
+Reduce have an empty diff. Entries are rendered with the DisplayFunction of "a".
+*/
+func Diff[D any, H comparable](a, b *Odds[D, H]) *DiffReport[D, H] {
+	report := &DiffReport[D, H]{displayFunction: a.DisplayFunction}
+
+	for hash, entry := range a.Map {
+		before := a.WeightAsProbability(entry.Weight)
+		after := b.ProbabilityOfHash(hash)
+
+		switch {
+		case before.Cmp(after) == 0:
+			continue
+		case after.Sign() == 0:
+			report.Removed = append(report.Removed, &DiffEntry[D, H]{Diff_Removed, hash, entry.Data, before, after})
+		case before.Sign() == 0:
+			report.Added = append(report.Added, &DiffEntry[D, H]{Diff_Added, hash, entry.Data, before, after})
+		default:
+			report.Reweighted = append(report.Reweighted, &DiffEntry[D, H]{Diff_Reweighted, hash, entry.Data, before, after})
+		}
+	}
+
+	for hash, entry := range b.Map {
+		if _, exists := a.Map[hash]; exists {
+			continue
+		}
+		after := b.WeightAsProbability(entry.Weight)
+		if after.Sign() != 0 {
+			report.Added = append(report.Added, &DiffEntry[D, H]{Diff_Added, hash, entry.Data, new(big.Rat), after})
+		}
+	}
+
+	sortByChange(report.Added)
+	sortByChange(report.Removed)
+	sortByChange(report.Reweighted)
+
+	return report
+}
+
+// Returns true if there are no differences
+func (report *DiffReport[D, H]) Empty() bool {
+	return len(report.Added) == 0 && len(report.Removed) == 0 && len(report.Reweighted) == 0
+}
+
+// Returns every difference in a single list, sorted by absolute change
+func (report *DiffReport[D, H]) All() []*DiffEntry[D, H] {
+	all := []*DiffEntry[D, H]{}
+	all = append(all, report.Added...)
+	all = append(all, report.Removed...)
+	all = append(all, report.Reweighted...)
+	sortByChange(all)
+	return all
+}
+
+/*
+Renders one line per difference, sorted by absolute change. Each line is marked
+with "+" for added, "-" for removed or "~" for reweighted entries, followed by
+the entry, its probabilities before and after, and the change.
+*/
+func (report *DiffReport[D, H]) String() string {
+	if report.Empty() {
+		return "no differences"
+	}
+
+	lines := []string{}
+	for _, d := range report.All() {
+		marker := "~"
+		if d.Kind == Diff_Added {
+			marker = "+"
+		} else if d.Kind == Diff_Removed {
+			marker = "-"
+		}
+
+		change := d.Change()
+		sign := ""
+		if change.Sign() > 0 {
+			sign = "+"
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %s: %s -> %s (%s%s)",
+			marker,
+			report.displayFunction(d.Data),
+			d.Before.RatString(),
+			d.After.RatString(),
+			sign,
+			change.RatString(),
+		))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Largest absolute change first, with ties broken by hash so the order is stable
+func sortByChange[D any, H comparable](entries []*DiffEntry[D, H]) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		changeI := entries[i].Change()
+		changeJ := entries[j].Change()
+		if c := changeI.Abs(changeI).Cmp(changeJ.Abs(changeJ)); c != 0 {
+			return c > 0
+		}
+		return fmt.Sprint(entries[i].Hash) < fmt.Sprint(entries[j].Hash)
+	})
+}
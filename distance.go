@@ -0,0 +1,241 @@
+package odds
+
+import (
+	"math"
+	"math/big"
+	"sort"
+)
+
+///////////////////////
+// DISTANCES BY HASH //
+///////////////////////
+
+/*
+Returns the exact total variation distance between "p" and "q", which is the
+largest difference in probability the two can assign to any event. Entries are
+matched up by their hash.
+*/
+func TotalVariation[D any, H comparable](p, q *Odds[D, H]) (*big.Rat, error) {
+	if p.Total.Sign() == 0 || q.Total.Sign() == 0 {
+		return nil, ErrZeroTotal
+	}
+
+	/*
+		|p(x) - q(x)| = |weightP * totalQ - weightQ * totalP| / (totalP * totalQ),
+		so the numerators can all be summed before a single division.
+	*/
+	sum := big.NewInt(0)
+	difference := new(big.Int)
+	addDifference := func(pWeight, qWeight *big.Int) {
+		difference.Mul(pWeight, q.Total)
+		difference.Sub(difference, new(big.Int).Mul(qWeight, p.Total))
+		sum.Add(sum, difference.Abs(difference))
+	}
+
+	zero := big.NewInt(0)
+	for hash, entry := range p.Map {
+		if qEntry := q.Map[hash]; qEntry != nil {
+			addDifference(entry.Weight, qEntry.Weight)
+		} else {
+			addDifference(entry.Weight, zero)
+		}
+	}
+	for hash, entry := range q.Map {
+		if _, exists := p.Map[hash]; !exists {
+			addDifference(zero, entry.Weight)
+		}
+	}
+
+	denominator := new(big.Int).Mul(p.Total, q.Total)
+	return new(big.Rat).SetFrac(sum, denominator.Lsh(denominator, 1)), nil
+}
+
+/*
+Returns the Hellinger distance between "p" and "q", between 0 and 1. Entries are
+matched up by their hash.
+*/
+func Hellinger[D any, H comparable](p, q *Odds[D, H]) (float64, error) {
+	if p.Total.Sign() == 0 || q.Total.Sign() == 0 {
+		return 0, ErrZeroTotal
+	}
+
+	// Bhattacharyya coefficient, the sum of sqrt(p(x) * q(x))
+	coefficient := 0.0
+	for hash, entry := range p.Map {
+		qEntry := q.Map[hash]
+		if qEntry == nil {
+			continue
+		}
+		product := new(big.Rat).Mul(p.WeightAsProbability(entry.Weight), q.WeightAsProbability(qEntry.Weight))
+		coefficient += math.Sqrt(ratFloat(product))
+	}
+
+	return math.Sqrt(math.Max(0, 1-coefficient)), nil
+}
+
+////////////////////////
+// DISTANCES BY VALUE //
+////////////////////////
+
+/*
+Returns the exact first Wasserstein (earth mover's) distance between "p" and
+"q", where the outcomes of both are placed on the number line by "value".
+*/
+func Wasserstein1[D any, H comparable](p, q *Odds[D, H], value func(D) *big.Rat) (*big.Rat, error) {
+	points, differences, err := cumulativeDifferences(p, q, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Area between the two step functions
+	distance := new(big.Rat)
+	width := new(big.Rat)
+	for i := 0; i+1 < len(points); i++ {
+		width.Sub(points[i+1], points[i])
+		height := new(big.Rat).SetInt(new(big.Int).Abs(differences[i]))
+		distance.Add(distance, height.Mul(height, width))
+	}
+
+	return distance.Quo(distance, new(big.Rat).SetInt(new(big.Int).Mul(p.Total, q.Total))), nil
+}
+
+/*
+Returns the exact Kolmogorov-Smirnov statistic between "p" and "q", which is
+the largest difference between their cumulative distributions, where the
+outcomes of both are placed on the number line by "value".
+*/
+func KolmogorovSmirnov[D any, H comparable](p, q *Odds[D, H], value func(D) *big.Rat) (*big.Rat, error) {
+	_, differences, err := cumulativeDifferences(p, q, value)
+	if err != nil {
+		return nil, err
+	}
+
+	largest := big.NewInt(0)
+	for _, difference := range differences {
+		if new(big.Int).Abs(difference).Cmp(largest) > 0 {
+			largest.Abs(difference)
+		}
+	}
+
+	return new(big.Rat).SetFrac(largest, new(big.Int).Mul(p.Total, q.Total)), nil
+}
+
+/*
+Places the outcomes of "p" and "q" on the number line. Returns every distinct
+value in increasing order, along with the difference of the cumulative
+distributions of "p" and "q" at that value, multiplied by p.Total * q.Total to
+keep everything in integers.
+*/
+func cumulativeDifferences[D any, H comparable](
+	p, q *Odds[D, H],
+	value func(D) *big.Rat,
+) ([]*big.Rat, []*big.Int, error) {
+
+	if p.Total.Sign() == 0 || q.Total.Sign() == 0 {
+		return nil, nil, ErrZeroTotal
+	}
+
+	// Each weight is scaled by the total of the other odds
+	type point struct {
+		value  *big.Rat
+		weight *big.Int
+	}
+	pointMap := map[string]*point{}
+	addPoint := func(data D, weight, scale *big.Int, sign int) {
+		v := value(data)
+		key := v.RatString()
+		existing := pointMap[key]
+		if existing == nil {
+			existing = &point{v, big.NewInt(0)}
+			pointMap[key] = existing
+		}
+		scaled := new(big.Int).Mul(weight, scale)
+		if sign < 0 {
+			scaled.Neg(scaled)
+		}
+		existing.weight.Add(existing.weight, scaled)
+	}
+
+	for _, entry := range p.Map {
+		addPoint(entry.Data, entry.Weight, q.Total, 1)
+	}
+	for _, entry := range q.Map {
+		addPoint(entry.Data, entry.Weight, p.Total, -1)
+	}
+
+	points := make([]*point, 0, len(pointMap))
+	for _, pt := range pointMap {
+		points = append(points, pt)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].value.Cmp(points[j].value) < 0
+	})
+
+	values := make([]*big.Rat, len(points))
+	differences := make([]*big.Int, len(points))
+	running := big.NewInt(0)
+	for i, pt := range points {
+		running.Add(running, pt.weight)
+		values[i] = pt.value
+		differences[i] = new(big.Int).Set(running)
+	}
+
+	return values, differences, nil
+}
+
+//////////////
+// EQUALITY //
+//////////////
+
+/*
+Returns true if "a" and "b" assign exactly the same probability to every hash,
+regardless of their raw weights. Two odds with no weight are equal.
+*/
+func Equal[D any, H comparable](a, b *Odds[D, H]) bool {
+	if a.Total.Sign() == 0 || b.Total.Sign() == 0 {
+		return a.Total.Sign() == b.Total.Sign()
+	}
+
+	// a(x) = b(x) exactly when weightA * totalB = weightB * totalA
+	left, right := new(big.Int), new(big.Int)
+	sameProbability := func(weightA, weightB *big.Int) bool {
+		return left.Mul(weightA, b.Total).Cmp(right.Mul(weightB, a.Total)) == 0
+	}
+
+	zero := big.NewInt(0)
+	for hash, entry := range a.Map {
+		weightB := zero
+		if entryB := b.Map[hash]; entryB != nil {
+			weightB = entryB.Weight
+		}
+		if !sameProbability(entry.Weight, weightB) {
+			return false
+		}
+	}
+	for hash, entry := range b.Map {
+		if _, exists := a.Map[hash]; !exists && entry.Weight.Sign() != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Returns true if the total variation distance between "a" and "b" is at most
+"tolerance". Two odds with no weight are equal, but odds with no weight are
+never close to odds with weight.
+*/
+func ApproxEqual[D any, H comparable](a, b *Odds[D, H], tolerance float64) bool {
+	if a.Total.Sign() == 0 || b.Total.Sign() == 0 {
+		return a.Total.Sign() == b.Total.Sign()
+	}
+
+	distance, err := TotalVariation(a, b)
+	if err != nil {
+		return false
+	}
+
+	limit := new(big.Rat).SetFloat64(tolerance)
+	return limit != nil && distance.Cmp(limit) <= 0
+}
@@ -41,6 +41,15 @@ func (o *Odds[D, H]) CopyEntry(entry *Entry[D, H]) *Entry[D, H] {
 	}
 }
 
+/*
+Create a new entry with the same hash and data, but with its own copy of the
+weight. Unlike CopyEntry, the data itself is shared, so no CopyFunction is
+needed. Used by operations which must not mutate the weights of their inputs.
+*/
+func (e *Entry[D, H]) clone() *Entry[D, H] {
+	return &Entry[D, H]{e.Hash, e.Data, new(big.Int).Set(e.Weight)}
+}
+
 ////////////////////////
 // ODDS ENTRY METHODS //
 ////////////////////////
@@ -79,3 +88,15 @@ func (o *Odds[D, H]) EntriesByWeight() []*Entry[D, H] {
 
 	return entries
 }
+
+/*
+Get a list of all the entries, sorted by their hash. Unlike Entries, the order
+is the same every time for the same set of hashes.
+*/
+func (o *Odds[D, H]) EntriesByHash() []*Entry[D, H] {
+	entries := o.Entries()
+	sort.Slice(entries, func(i, j int) bool {
+		return compareHashes(entries[i].Hash, entries[j].Hash) < 0
+	})
+	return entries
+}
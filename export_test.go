@@ -0,0 +1,4 @@
+package odds
+
+// Lets the external tests clean up the names they register
+var Unregister = unregister
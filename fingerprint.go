@@ -0,0 +1,56 @@
+package odds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"sort"
+)
+
+/*
+Returns a deterministic SHA-256 digest of the distribution of "o", suitable as
+a cache key. Each hash is turned into bytes by "encodeHash", which must give
+distinct bytes for distinct hashes. The digest covers the reduced weights of
+the entries sorted by their encoded hash, so it does not depend on map
+iteration order, Scale or Reduce. Entries with no weight are ignored. "o" is
+not modified.
+*/
+func (o *Odds[D, H]) Fingerprint(encodeHash func(H) []byte) [sha256.Size]byte {
+	type encodedEntry struct {
+		hash   []byte
+		weight *big.Int
+	}
+
+	entries := []encodedEntry{}
+	gcd := big.NewInt(0)
+	for _, entry := range o.Map {
+		if entry.Weight.Sign() == 0 {
+			continue
+		}
+		entries = append(entries, encodedEntry{encodeHash(entry.Hash), entry.Weight})
+		gcd.GCD(nil, nil, gcd, entry.Weight)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].hash, entries[j].hash) < 0
+	})
+
+	digest := sha256.New()
+	buf := make([]byte, binary.MaxVarintLen64)
+	writeBytes := func(b []byte) {
+		digest.Write(buf[:binary.PutUvarint(buf, uint64(len(b)))])
+		digest.Write(b)
+	}
+
+	digest.Write(buf[:binary.PutUvarint(buf, uint64(len(entries)))])
+	reduced := new(big.Int)
+	for _, entry := range entries {
+		writeBytes(entry.hash)
+		writeBytes(reduced.Quo(entry.weight, gcd).Bytes())
+	}
+
+	var fingerprint [sha256.Size]byte
+	copy(fingerprint[:], digest.Sum(nil))
+	return fingerprint
+}
@@ -0,0 +1,191 @@
+package odds
+
+import (
+	"math"
+	"math/big"
+)
+
+/////////////
+// ENTROPY //
+/////////////
+
+/*
+Returns the Shannon entropy of "o" in bits. Probabilities are kept exact until
+the final logarithm, so even odds with enormous weights give accurate results.
+*/
+func Entropy[D any, H comparable](o *Odds[D, H]) (float64, error) {
+	if o.Total.Sign() == 0 {
+		return 0, ErrZeroTotal
+	}
+
+	entropy := 0.0
+	for _, entry := range o.Map {
+		if entry.Weight.Sign() == 0 {
+			continue
+		}
+		p := o.WeightAsProbability(entry.Weight)
+		entropy -= ratFloat(p) * log2Rat(p)
+	}
+	return entropy, nil
+}
+
+/*
+Returns the cross entropy of "q" relative to "p" in bits, matching entries by
+hash. Returns +Inf if "p" has weight on a hash that "q" has no weight on.
+*/
+func CrossEntropy[D any, H comparable](p, q *Odds[D, H]) (float64, error) {
+	if p.Total.Sign() == 0 || q.Total.Sign() == 0 {
+		return 0, ErrZeroTotal
+	}
+
+	crossEntropy := 0.0
+	for hash, entry := range p.Map {
+		if entry.Weight.Sign() == 0 {
+			continue
+		}
+		qEntry := q.Map[hash]
+		if qEntry == nil || qEntry.Weight.Sign() == 0 {
+			return math.Inf(1), nil
+		}
+		crossEntropy -= ratFloat(p.WeightAsProbability(entry.Weight)) * log2Rat(q.WeightAsProbability(qEntry.Weight))
+	}
+	return crossEntropy, nil
+}
+
+/*
+Returns the Kullback-Leibler divergence of "q" from "p" in bits, matching
+entries by hash. Returns +Inf if "p" has weight on a hash that "q" has no weight
+on.
+*/
+func KLDivergence[D any, H comparable](p, q *Odds[D, H]) (float64, error) {
+	if p.Total.Sign() == 0 || q.Total.Sign() == 0 {
+		return 0, ErrZeroTotal
+	}
+
+	divergence := 0.0
+	for hash, entry := range p.Map {
+		if entry.Weight.Sign() == 0 {
+			continue
+		}
+		qEntry := q.Map[hash]
+		if qEntry == nil || qEntry.Weight.Sign() == 0 {
+			return math.Inf(1), nil
+		}
+
+		// p(x) / q(x) = weightP * totalQ / (totalP * weightQ)
+		ratio := new(big.Rat).SetFrac(
+			new(big.Int).Mul(entry.Weight, q.Total),
+			new(big.Int).Mul(p.Total, qEntry.Weight),
+		)
+		divergence += ratFloat(p.WeightAsProbability(entry.Weight)) * log2Rat(ratio)
+	}
+	return divergence, nil
+}
+
+/*
+Returns the Jensen-Shannon divergence between "p" and "q" in bits, matching
+entries by hash. Unlike KLDivergence it is symmetric and always between 0 and 1.
+*/
+func JensenShannon[D any, H comparable](p, q *Odds[D, H]) (float64, error) {
+	if p.Total.Sign() == 0 || q.Total.Sign() == 0 {
+		return 0, ErrZeroTotal
+	}
+
+	// Half the KL divergence of each odds from the midpoint m = (p + q) / 2
+	divergence := 0.0
+	half := big.NewRat(1, 2)
+	addTerm := func(x, y *big.Rat) {
+		if x.Sign() == 0 {
+			return
+		}
+		m := new(big.Rat).Add(x, y)
+		m.Mul(m, half)
+		divergence += ratFloat(x) * log2Rat(new(big.Rat).Quo(x, m)) / 2
+	}
+
+	for hash, entry := range p.Map {
+		pProbability := p.WeightAsProbability(entry.Weight)
+		qProbability := q.ProbabilityOfHash(hash)
+		addTerm(pProbability, qProbability)
+		addTerm(qProbability, pProbability)
+	}
+	for hash, entry := range q.Map {
+		if _, exists := p.Map[hash]; !exists {
+			addTerm(q.WeightAsProbability(entry.Weight), new(big.Rat))
+		}
+	}
+
+	return divergence, nil
+}
+
+/*
+Returns the mutual information in bits between the two keys each entry of "o"
+is split into. "o" is treated as the joint distribution of the keys.
+*/
+func MutualInformation[D any, H comparable, K1 comparable, K2 comparable](
+	o *Odds[D, H],
+	split func(D) (K1, K2),
+) (float64, error) {
+	if o.Total.Sign() == 0 {
+		return 0, ErrZeroTotal
+	}
+
+	joint := map[Pair[K1, K2]]*big.Int{}
+	firstWeights := map[K1]*big.Int{}
+	secondWeights := map[K2]*big.Int{}
+
+	for _, entry := range o.Map {
+		k1, k2 := split(entry.Data)
+		addTo(joint, Pair[K1, K2]{k1, k2}, entry.Weight)
+		addTo(firstWeights, k1, entry.Weight)
+		addTo(secondWeights, k2, entry.Weight)
+	}
+
+	// p(x, y) / (p(x) p(y)) = weight(x, y) * total / (weight(x) * weight(y))
+	information := 0.0
+	for keys, weight := range joint {
+		if weight.Sign() == 0 {
+			continue
+		}
+		ratio := new(big.Rat).SetFrac(
+			new(big.Int).Mul(weight, o.Total),
+			new(big.Int).Mul(firstWeights[keys.First], secondWeights[keys.Second]),
+		)
+		information += ratFloat(o.WeightAsProbability(weight)) * log2Rat(ratio)
+	}
+	return information, nil
+}
+
+/////////////
+// HELPERS //
+/////////////
+
+// Adds "weight" to the weight stored under "key", without aliasing "weight"
+func addTo[K comparable](weights map[K]*big.Int, key K, weight *big.Int) {
+	if existing, exists := weights[key]; exists {
+		existing.Add(existing, weight)
+	} else {
+		weights[key] = new(big.Int).Set(weight)
+	}
+}
+
+func ratFloat(r *big.Rat) float64 {
+	f, _ := r.Float64()
+	return f
+}
+
+// Base 2 logarithm of a positive rational, without overflowing for huge values
+func log2Rat(r *big.Rat) float64 {
+	return log2Int(r.Num()) - log2Int(r.Denom())
+}
+
+// Base 2 logarithm of a positive integer of any size
+func log2Int(x *big.Int) float64 {
+	shift := x.BitLen() - 64
+	if shift <= 0 {
+		f, _ := new(big.Float).SetInt(x).Float64()
+		return math.Log2(f)
+	}
+	top, _ := new(big.Float).SetInt(new(big.Int).Rsh(x, uint(shift))).Float64()
+	return math.Log2(top) + float64(shift)
+}
@@ -0,0 +1,136 @@
+package odds
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+/////////////////
+// DATA CODECS //
+/////////////////
+
+/*
+Encode function which can be used for any data type that is supported by the
+encoding/json package.
+*/
+func JSONEncode[D any](data D) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+/*
+Decode function which can be used for any data type that is supported by the
+encoding/json package.
+*/
+func JSONDecode[D any](b []byte) (D, error) {
+	var data D
+	err := json.Unmarshal(b, &data)
+	return data, err
+}
+
+//////////////////////
+// JSON DEFINITIONS //
+//////////////////////
+
+type jsonOdds struct {
+	Type    string      `json:"type,omitempty"`
+	Total   string      `json:"total"`
+	Entries []jsonEntry `json:"entries"`
+}
+
+type jsonEntry struct {
+	Data   json.RawMessage `json:"data"`
+	Weight string          `json:"weight"`
+}
+
+///////////////////
+// JSON ENCODING //
+///////////////////
+
+/*
+Encodes "o" as JSON. Every entry.Data is encoded with o.EncodeFunction, which
+must produce valid JSON (see JSONEncode). Weights and the total are written as
+decimal strings so no precision is lost.
+*/
+func (o *Odds[D, H]) MarshalJSON() ([]byte, error) {
+	if o.EncodeFunction == nil {
+		return nil, fmt.Errorf("odds: no EncodeFunction provided")
+	}
+
+	encoded := jsonOdds{
+		Type:    o.TypeName,
+		Total:   o.Total.String(),
+		Entries: make([]jsonEntry, 0, len(o.Map)),
+	}
+
+	for _, entry := range o.Map {
+		data, err := o.EncodeFunction(entry.Data)
+		if err != nil {
+			return nil, fmt.Errorf("odds: encoding data: %w", err)
+		}
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("odds: EncodeFunction produced invalid JSON %q", data)
+		}
+		encoded.Entries = append(encoded.Entries, jsonEntry{data, entry.Weight.String()})
+	}
+
+	return json.Marshal(encoded)
+}
+
+/*
+Decodes JSON produced by MarshalJSON into "o", replacing any existing entries.
+If "o" has no DecodeFunction, all of its functions are taken from the options
+registered under the type name stored in the JSON (see Register). The Map is
+rebuilt by hashing each decoded entry.Data, and the stored total is checked
+against the decoded weights. On any error "o" is left untouched.
+*/
+func (o *Odds[D, H]) UnmarshalJSON(b []byte) error {
+	var decoded jsonOdds
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+
+	// Decode into a fresh odds object so a failure never leaves "o" half loaded
+	var options *OddsOptions[D, H]
+	var result *Odds[D, H]
+	if o.DecodeFunction == nil {
+		var err error
+		if options, err = Lookup[D, H](decoded.Type); err != nil {
+			return err
+		}
+		result = options.Odds()
+	} else {
+		result = NewOddsFromReference(o)
+	}
+
+	total, ok := new(big.Int).SetString(decoded.Total, 10)
+	if !ok {
+		return fmt.Errorf("odds: invalid total %q", decoded.Total)
+	}
+
+	for _, entry := range decoded.Entries {
+		weight, ok := new(big.Int).SetString(entry.Weight, 10)
+		if !ok || weight.Sign() < 0 {
+			return fmt.Errorf("odds: invalid weight %q", entry.Weight)
+		}
+
+		data, err := result.DecodeFunction(entry.Data)
+		if err != nil {
+			return fmt.Errorf("odds: decoding data: %w", err)
+		}
+
+		result.Add(data, weight)
+	}
+
+	if result.Total.Cmp(total) != 0 {
+		return fmt.Errorf("odds: total %s does not match sum of weights %s", total, result.Total)
+	}
+
+	if options != nil {
+		options.applyTo(o)
+	}
+	o.Map = result.Map
+	o.Total = result.Total
+
+	return nil
+}
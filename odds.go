@@ -18,6 +18,10 @@ type Odds[D any, H comparable] struct {
 	// Total weight of entries in the odds map
 	Total *big.Int
 
+	// Name the options of the odds are registered under. Written into every
+	// serialized snapshot so the functions can be reattached when loading.
+	TypeName string
+
 	// CUSTOMIZABLE FUNCTIONS //
 
 	// How each entry.Data is hashed
@@ -43,6 +47,12 @@ type Odds[D any, H comparable] struct {
 	// How each data object should be displayed
 	DisplayFunction func(D) string
 
+	// How each entry.Data is encoded to bytes when the odds are serialized
+	EncodeFunction func(D) ([]byte, error)
+
+	// How bytes produced by EncodeFunction are decoded back into entry.Data
+	DecodeFunction func([]byte) (D, error)
+
 	// Used for sync operations
 	lock sync.Mutex
 }
@@ -68,6 +78,7 @@ func (o *Odds[D, H]) Copy() *Odds[D, H] {
 Options for creating a new Odds Object
 */
 type OddsOptions[D any, H comparable] struct {
+	TypeName                string
 	HashFunction            func(D) H
 	CopyFunction            func(D) D
 	CombineFunction         func(D, D) D
@@ -75,6 +86,8 @@ type OddsOptions[D any, H comparable] struct {
 	ConvolveFunction        func(*Odds[D, H], *Entry[D, H], *Entry[D, H]) []*Entry[D, H]
 	ConvolveInPlaceFunction func(*Odds[D, H], *Entry[D, H], *Entry[D, H])
 	DisplayFunction         func(D) string
+	EncodeFunction          func(D) ([]byte, error)
+	DecodeFunction          func([]byte) (D, error)
 }
 
 // OPTIONS CONSTRUCTORS //
@@ -89,6 +102,14 @@ func NewOptions[D any, H comparable](hashFunction func(D) H) *OddsOptions[D, H]
 	}
 }
 
+/*
+Specify the type name in the options. Set automatically by Register.
+*/
+func (options *OddsOptions[D, H]) WithTypeName(typeName string) *OddsOptions[D, H] {
+	options.TypeName = typeName
+	return options
+}
+
 /*
 Specify the hash function in the options
 */
@@ -149,6 +170,22 @@ func (options *OddsOptions[D, H]) WithDisplay(displayFunction func(D) string) *O
 	return options
 }
 
+/*
+Specify the encode function in the options
+*/
+func (options *OddsOptions[D, H]) WithEncode(encodeFunction func(D) ([]byte, error)) *OddsOptions[D, H] {
+	options.EncodeFunction = encodeFunction
+	return options
+}
+
+/*
+Specify the decode function in the options
+*/
+func (options *OddsOptions[D, H]) WithDecode(decodeFunction func([]byte) (D, error)) *OddsOptions[D, H] {
+	options.DecodeFunction = decodeFunction
+	return options
+}
+
 /////////////////////////////
 // INSTANTIATION FUNCTIONS //
 /////////////////////////////
@@ -159,20 +196,28 @@ provided. If not all functions are provided, some features will cause crashes
 if used.
 */
 func (options *OddsOptions[D, H]) Odds() *Odds[D, H] {
-	return &Odds[D, H]{
+	o := &Odds[D, H]{
 		Map:   map[H]*Entry[D, H]{},
 		Total: big.NewInt(0),
 
-		HashFunction:            options.HashFunction,
-		CopyFunction:            options.CopyFunction,
-		CombineFunction:         options.CombineFunction,
-		CombineInPlaceFunction:  options.CombineInPlaceFunction,
-		ConvolveFunction:        options.ConvolveFunction,
-		ConvolveInPlaceFunction: options.ConvolveInPlaceFunction,
-		DisplayFunction:         options.DisplayFunction,
-
 		lock: sync.Mutex{},
 	}
+	options.applyTo(o)
+	return o
+}
+
+// Sets all the customizable functions of "o" to the ones in the options
+func (options *OddsOptions[D, H]) applyTo(o *Odds[D, H]) {
+	o.TypeName = options.TypeName
+	o.HashFunction = options.HashFunction
+	o.CopyFunction = options.CopyFunction
+	o.CombineFunction = options.CombineFunction
+	o.CombineInPlaceFunction = options.CombineInPlaceFunction
+	o.ConvolveFunction = options.ConvolveFunction
+	o.ConvolveInPlaceFunction = options.ConvolveInPlaceFunction
+	o.DisplayFunction = options.DisplayFunction
+	o.EncodeFunction = options.EncodeFunction
+	o.DecodeFunction = options.DecodeFunction
 }
 
 /*
@@ -181,12 +226,15 @@ Create a new Odds object based on the reference.
 func NewOddsFromReference[D any, H comparable](reference *Odds[D, H]) *Odds[D, H] {
 	newOdds := NewOptions(reference.HashFunction).Odds()
 
+	newOdds.TypeName = reference.TypeName
 	newOdds.CopyFunction = reference.CopyFunction
 	newOdds.CombineFunction = reference.CombineFunction
 	newOdds.CombineInPlaceFunction = reference.CombineInPlaceFunction
 	newOdds.ConvolveFunction = reference.ConvolveFunction
 	newOdds.ConvolveInPlaceFunction = reference.ConvolveInPlaceFunction
 	newOdds.DisplayFunction = reference.DisplayFunction
+	newOdds.EncodeFunction = reference.EncodeFunction
+	newOdds.DecodeFunction = reference.DecodeFunction
 
 	return newOdds
 }
@@ -202,6 +250,14 @@ func (o *Odds[D, H]) AsReference() *Odds[D, H] {
 // MODIFICATIONS //
 ///////////////////
 
+/*
+Specify the type name in the odds
+*/
+func (o *Odds[D, H]) WithTypeName(typeName string) *Odds[D, H] {
+	o.TypeName = typeName
+	return o
+}
+
 /*
 Specify the copy function in the odds
 */
@@ -302,6 +358,22 @@ func (o *Odds[D, H]) WithDisplay(displayFunction func(D) string) *Odds[D, H] {
 	return o
 }
 
+/*
+Specify the encode function in the odds
+*/
+func (o *Odds[D, H]) WithEncode(encodeFunction func(D) ([]byte, error)) *Odds[D, H] {
+	o.EncodeFunction = encodeFunction
+	return o
+}
+
+/*
+Specify the decode function in the odds
+*/
+func (o *Odds[D, H]) WithDecode(decodeFunction func([]byte) (D, error)) *Odds[D, H] {
+	o.DecodeFunction = decodeFunction
+	return o
+}
+
 /////////////
 // HELPERS //
 /////////////
@@ -0,0 +1,92 @@
+package odds
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+Deterministic total order over any comparable hash, used wherever entries have
+to be visited in the same order from run to run. Common types are compared
+directly. Anything else is compared by reflection: numbers and strings by
+value, arrays and structs field by field, and interfaces by dynamic type first.
+Pointers and channels can only be compared by address, so they are only stable
+for the lifetime of the process.
+*/
+func compareHashes[H comparable](a, b H) int {
+	// With an interface hash "b" can hold a different type, so check it too
+	switch x := any(a).(type) {
+	case int:
+		if y, ok := any(b).(int); ok {
+			return cmp.Compare(x, y)
+		}
+	case int64:
+		if y, ok := any(b).(int64); ok {
+			return cmp.Compare(x, y)
+		}
+	case uint64:
+		if y, ok := any(b).(uint64); ok {
+			return cmp.Compare(x, y)
+		}
+	case string:
+		if y, ok := any(b).(string); ok {
+			return strings.Compare(x, y)
+		}
+	}
+	return compareValues(reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+}
+
+func compareValues(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Bool:
+		return cmp.Compare(boolRank(a.Bool()), boolRank(b.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		if c := cmp.Compare(real(a.Complex()), real(b.Complex())); c != 0 {
+			return c
+		}
+		return cmp.Compare(imag(a.Complex()), imag(b.Complex()))
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if c := compareValues(a.Index(i), b.Index(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if c := compareValues(a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return cmp.Compare(boolRank(!a.IsNil()), boolRank(!b.IsNil()))
+		}
+		a, b = a.Elem(), b.Elem()
+		if a.Type() != b.Type() {
+			return strings.Compare(a.Type().String(), b.Type().String())
+		}
+		return compareValues(a, b)
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return cmp.Compare(a.Pointer(), b.Pointer())
+	}
+	panic(fmt.Sprintf("odds: cannot order values of kind %s", a.Kind()))
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+package odds
+
+import (
+	"fmt"
+	"math/big"
+)
+
+/*
+An entry whose weight has been divided by the total of its odds, giving the
+exact probability of the entry.
+*/
+type NormalizedEntry[D any, H comparable] struct {
+	Hash        H
+	Data        D
+	Probability *big.Rat
+}
+
+/*
+Returns weight / o.Total as an exact rational. Returns 0 if "o" has no weight.
+*/
+func (o *Odds[D, H]) WeightAsProbability(weight *big.Int) *big.Rat {
+	if o.Total.Sign() == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(weight, o.Total)
+}
+
+/*
+Returns the exact probability of the data in "o", using the hash function of
+"o". Returns 0 if the data does not exist in "o".
+*/
+func (o *Odds[D, H]) Probability(data D) *big.Rat {
+	return o.ProbabilityOfHash(o.HashFunction(data))
+}
+
+/*
+Returns the exact probability of the entry with the given hash. Returns 0 if
+there is no such entry.
+*/
+func (o *Odds[D, H]) ProbabilityOfHash(hash H) *big.Rat {
+	entry := o.Map[hash]
+	if entry == nil {
+		return new(big.Rat)
+	}
+	return o.WeightAsProbability(entry.Weight)
+}
+
+/*
+Returns the exact probability that an entry of "o" satisfies the condition.
+*/
+func (o *Odds[D, H]) ProbabilityOf(condition func(*Entry[D, H]) bool) *big.Rat {
+	return o.WeightAsProbability(o.ConditionWeight(condition))
+}
+
+/*
+Get a list of all the entries with their exact probabilities, sorted by their
+contribution. "o" is not modified.
+*/
+func (o *Odds[D, H]) Normalized() []*NormalizedEntry[D, H] {
+	entries := o.EntriesByWeight()
+	normalized := make([]*NormalizedEntry[D, H], len(entries))
+	for i, entry := range entries {
+		normalized[i] = &NormalizedEntry[D, H]{
+			entry.Hash,
+			entry.Data,
+			o.WeightAsProbability(entry.Weight),
+		}
+	}
+	return normalized
+}
+
+/////////////
+// UPDATES //
+/////////////
+
+/*
+Performs a Bayesian update of "o" in place. The weight of every entry is
+multiplied by its likelihood, computed exactly by bringing all the likelihoods
+to a common denominator, and the odds are then reduced. Entries with a
+likelihood of 0 are removed. Returns the marginal likelihood (evidence) of the
+observation, which is the probability weighted sum of the likelihoods.
+
+Returns ErrZeroProbability, leaving "o" unchanged, if the evidence is 0.
+*/
+func (o *Odds[D, H]) Update(likelihood func(*Entry[D, H]) *big.Rat) (*big.Rat, error) {
+	entries := o.Entries()
+	likelihoods := make([]*big.Rat, len(entries))
+
+	denominator := big.NewInt(1)
+	for i, entry := range entries {
+		l := likelihood(entry)
+		if l.Sign() < 0 {
+			return nil, fmt.Errorf("odds: negative likelihood %s", l.RatString())
+		}
+		likelihoods[i] = l
+
+		// Least common multiple of all the denominators seen so far
+		gcd := new(big.Int).GCD(nil, nil, denominator, l.Denom())
+		denominator.Mul(denominator, new(big.Int).Quo(l.Denom(), gcd))
+	}
+
+	factors := make([]*big.Int, len(entries))
+	for i, l := range likelihoods {
+		factors[i] = new(big.Int).Quo(denominator, l.Denom())
+		factors[i].Mul(factors[i], l.Num())
+	}
+
+	return o.updateWeights(entries, factors, denominator)
+}
+
+/*
+Same as Update, but with integer likelihoods, so every weight is simply
+multiplied by its likelihood. The returned evidence is the probability weighted
+sum of the likelihoods.
+*/
+func (o *Odds[D, H]) UpdateInt(likelihood func(*Entry[D, H]) *big.Int) (*big.Rat, error) {
+	entries := o.Entries()
+	factors := make([]*big.Int, len(entries))
+	for i, entry := range entries {
+		factors[i] = likelihood(entry)
+		if factors[i].Sign() < 0 {
+			return nil, fmt.Errorf("odds: negative likelihood %s", factors[i])
+		}
+	}
+
+	return o.updateWeights(entries, factors, big.NewInt(1))
+}
+
+/*
+Multiplies the weight of each entry by the matching factor. The factors are
+likelihoods which have all been multiplied by "denominator".
+*/
+func (o *Odds[D, H]) updateWeights(entries []*Entry[D, H], factors []*big.Int, denominator *big.Int) (*big.Rat, error) {
+	newWeights := make([]*big.Int, len(entries))
+	newTotal := big.NewInt(0)
+	for i, entry := range entries {
+		newWeights[i] = new(big.Int).Mul(entry.Weight, factors[i])
+		newTotal.Add(newTotal, newWeights[i])
+	}
+
+	if newTotal.Sign() == 0 {
+		return nil, ErrZeroProbability
+	}
+
+	evidence := new(big.Rat).SetFrac(newTotal, new(big.Int).Mul(o.Total, denominator))
+
+	for i, entry := range entries {
+		if newWeights[i].Sign() == 0 {
+			delete(o.Map, entry.Hash)
+		} else {
+			entry.Weight.Set(newWeights[i])
+		}
+	}
+	o.Total.Set(newTotal)
+	o.Reduce()
+
+	return evidence, nil
+}
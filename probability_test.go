@@ -0,0 +1,147 @@
+package odds_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/flywingedai/odds"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbability(t *testing.T) {
+	die := test_Die(6)
+
+	assert.Equal(t, big.NewRat(1, 6), die.Probability(3))
+	assert.Equal(t, big.NewRat(1, 6), die.ProbabilityOfHash(6))
+	assert.Equal(t, new(big.Rat), die.Probability(7))
+	assert.Equal(t, big.NewRat(1, 2), die.ProbabilityOf(func(e *odds.Entry[int, int]) bool {
+		return e.Data%2 == 0
+	}))
+
+	// Scaling the odds never changes the probabilities
+	die.Scale(big.NewInt(12))
+	total := new(big.Rat)
+	for _, entry := range die.Normalized() {
+		assert.Equal(t, big.NewRat(1, 6), entry.Probability)
+		total.Add(total, entry.Probability)
+	}
+	assert.Equal(t, big.NewRat(1, 1), total)
+}
+
+func TestCondition(t *testing.T) {
+	die := test_Die(6)
+
+	high, probability, err := die.Condition(func(e *odds.Entry[int, int]) bool { return e.Data > 4 })
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 3), probability)
+	assert.Equal(t, big.NewRat(1, 2), high.Probability(5))
+	assert.Equal(t, big.NewInt(2), high.Total)
+
+	// The original odds are untouched
+	assert.Equal(t, big.NewInt(6), die.Total)
+	assert.Equal(t, big.NewInt(1), die.Map[5].Weight)
+
+	_, _, err = die.Condition(func(e *odds.Entry[int, int]) bool { return e.Data > 6 })
+	assert.ErrorIs(t, err, odds.ErrZeroProbability)
+}
+
+func TestUpdate(t *testing.T) {
+
+	// A coin which is either fair or always lands heads
+	coins := odds.NewOptions(func(s string) string { return s }).Odds()
+	coins.Add("fair", big.NewInt(1))
+	coins.Add("biased", big.NewInt(1))
+
+	heads := func(e *odds.Entry[string, string]) *big.Rat {
+		if e.Data == "fair" {
+			return big.NewRat(1, 2)
+		}
+		return big.NewRat(1, 1)
+	}
+
+	evidence, err := coins.Update(heads)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(3, 4), evidence)
+	assert.Equal(t, big.NewRat(2, 3), coins.Probability("biased"))
+
+	evidence, err = coins.Update(heads)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(5, 6), evidence)
+	assert.Equal(t, big.NewRat(4, 5), coins.Probability("biased"))
+	assert.Equal(t, big.NewInt(5), coins.Total)
+
+	// Observing tails rules out the biased coin
+	evidence, err = coins.UpdateInt(func(e *odds.Entry[string, string]) *big.Int {
+		if e.Data == "fair" {
+			return big.NewInt(1)
+		}
+		return big.NewInt(0)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 5), evidence)
+	assert.Equal(t, 1, len(coins.Map))
+	assert.Equal(t, big.NewInt(1), coins.Total)
+
+	_, err = coins.UpdateInt(func(e *odds.Entry[string, string]) *big.Int { return big.NewInt(0) })
+	assert.ErrorIs(t, err, odds.ErrZeroProbability)
+	assert.Equal(t, big.NewInt(1), coins.Total)
+}
+
+func TestGroupBy(t *testing.T) {
+	die := test_Die(6)
+	die.Scale(big.NewInt(4))
+
+	groups, weights := odds.GroupBy(die, func(e *odds.Entry[int, int]) bool { return e.Data <= 2 })
+	assert.Equal(t, big.NewInt(8), weights[true])
+	assert.Equal(t, big.NewInt(16), weights[false])
+	assert.Equal(t, big.NewInt(2), groups[true].Total)
+	assert.Equal(t, big.NewRat(1, 4), groups[false].Probability(6))
+	assert.Equal(t, big.NewInt(24), die.Total)
+
+	ungrouped, err := odds.Ungroup(groups, weights)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(6), ungrouped.Total)
+	for i := 1; i <= 6; i++ {
+		assert.Equal(t, big.NewRat(1, 6), ungrouped.Probability(i))
+	}
+
+	// Ungrouping does not modify the groups
+	assert.Equal(t, big.NewInt(4), groups[false].Total)
+}
+
+func TestMixture(t *testing.T) {
+	d4, d6, d8 := test_Die(4), test_Die(6), test_Die(8)
+	weights := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+
+	mixture, err := odds.Mixture([]*odds.Odds[int, int]{d4, d6, d8}, weights)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1*6+2*4+3*3, 6*24), mixture.Probability(1))
+	assert.Equal(t, big.NewRat(3, 6*8), mixture.Probability(8))
+
+	// Same result as chaining AddOdds, where each weight is relative to the
+	// current total of the odds being added to
+	chained := test_Die(4).AddOdds(test_Die(6), big.NewInt(8))
+	chained.AddOdds(test_Die(8), new(big.Int).Set(chained.Total))
+	for i := 1; i <= 8; i++ {
+		assert.Equal(t, chained.Probability(i), mixture.Probability(i))
+	}
+	assert.Equal(t, chained.Reduce().Total, mixture.Total)
+	assert.Equal(t, big.NewInt(4), d4.Total)
+
+	_, err = odds.Mixture([]*odds.Odds[int, int]{d4}, weights)
+	assert.Error(t, err)
+}
+
+// Test Functions //
+
+// Odds of rolling a fair die with the given number of sides
+func test_Die(sides int) *odds.Odds[int, int] {
+	die := odds.NewOptions(func(i int) int { return i }).
+		WithCopy(func(i int) int { return i }).
+		WithAdd(func(i1, i2 int) int { return i1 }).
+		Odds()
+	for i := 1; i <= sides; i++ {
+		die.Add(i, big.NewInt(1))
+	}
+	return die
+}
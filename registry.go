@@ -0,0 +1,104 @@
+package odds
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var ErrUnknownType = errors.New("odds: unknown type name")
+
+var registry = struct {
+	sync.RWMutex
+	options map[string]any
+}{options: map[string]any{}}
+
+/*
+Register a copy of "options" under "name", so later changes to "options" do not
+affect what is registered. The name is also stored in options.TypeName, so
+every odds created from the options carries it into serialized snapshots, and
+loading a snapshot can look the options back up to return fully functional
+odds. Panics if the name is empty or already registered, or if the options
+have no HashFunction or DecodeFunction, since loading needs both.
+*/
+func Register[D any, H comparable](name string, options *OddsOptions[D, H]) {
+	if name == "" {
+		panic("odds: Register called with an empty name")
+	}
+	if options.HashFunction == nil {
+		panic(fmt.Sprintf("odds: Register called for %q with no HashFunction", name))
+	}
+	if options.DecodeFunction == nil {
+		panic(fmt.Sprintf("odds: Register called for %q with no DecodeFunction", name))
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.options[name]; exists {
+		panic(fmt.Sprintf("odds: Register called twice for %q", name))
+	}
+
+	options.TypeName = name
+	registered := *options
+	registry.options[name] = &registered
+}
+
+/*
+Find the options registered under "name". Returns a copy, so the registered
+options cannot be changed through it. Returns an error wrapping ErrUnknownType
+if nothing is registered under that name, or if the registered options are for
+different data or hash types.
+*/
+func Lookup[D any, H comparable](name string) (*OddsOptions[D, H], error) {
+	registry.RLock()
+	registered, exists := registry.options[name]
+	registry.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w %q", ErrUnknownType, name)
+	}
+
+	options, ok := registered.(*OddsOptions[D, H])
+	if !ok {
+		return nil, fmt.Errorf("%w %q for %T", ErrUnknownType, name, (*OddsOptions[D, H])(nil))
+	}
+	copied := *options
+	return &copied, nil
+}
+
+// Remove whatever is registered under "name". Only used by tests.
+func unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.options, name)
+}
+
+/////////////
+// LOADING //
+/////////////
+
+/*
+Load odds from JSON produced by MarshalJSON, using the options registered under
+the type name stored in the JSON.
+*/
+func LoadJSON[D any, H comparable](b []byte) (*Odds[D, H], error) {
+	o := &Odds[D, H]{}
+	if err := o.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+/*
+Load odds from a binary snapshot produced by WriteTo, using the options
+registered under the type name stored in the snapshot.
+*/
+func Load[D any, H comparable](r io.Reader) (*Odds[D, H], error) {
+	o := &Odds[D, H]{}
+	if _, err := o.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
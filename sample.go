@@ -1,21 +1,430 @@
 package odds
 
 import (
-	"crypto/rand"
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
 	"math/big"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 /*
-Get a single random sample from the odds object. Returns the full entry.
+Get a single random sample from the odds object using crypto/rand. Returns the
+full entry, or nil if the odds have no weight. Entries are visited in map
+order, which is cheap but means the draws cannot be reproduced, see SampleWith.
 */
 func (o *Odds[D, H]) Sample() *Entry[D, H] {
-	total := big.NewInt(0)
-	randPoint, _ := rand.Int(rand.Reader, o.Total)
+	if o.Total.Sign() <= 0 {
+		return nil
+	}
+
+	randPoint, _ := crand.Int(crand.Reader, o.Total)
+	return selectFromMap(o.Map, randPoint)
+}
+
+/*
+Get a single random sample from the odds object, reading random bytes from "r".
+Entries are visited in hash order, so the same bytes always select the same
+entry for the same distribution. Returns nil if the odds have no weight. Every
+call sorts the entries, so for repeated draws build a CumulativeSampler or
+AliasSampler once and sample from that instead.
+*/
+func (o *Odds[D, H]) SampleFrom(r io.Reader) (*Entry[D, H], error) {
+	if o.Total.Sign() <= 0 {
+		return nil, nil
+	}
+	randPoint, err := crand.Int(r, o.Total)
+	if err != nil {
+		return nil, err
+	}
+	return selectEntry(o.EntriesByHash(), randPoint), nil
+}
+
+/*
+Get a single random sample from the odds object using "src", such as a seeded
+rand.PCG. Entries are visited in hash order, so the same source state always
+selects the same entry for the same distribution. Returns nil if the odds have
+no weight. Every call sorts the entries, so for repeated draws build a
+CumulativeSampler or AliasSampler once and sample from that instead.
+*/
+func (o *Odds[D, H]) SampleWith(src rand.Source) *Entry[D, H] {
+	if o.Total.Sign() <= 0 {
+		return nil
+	}
+	return selectEntry(o.EntriesByHash(), randomBelow(src, o.Total))
+}
+
+///////////////////////////
+// CONDITIONAL SAMPLING //
+//////////////////////////
+
+/*
+Acceptance rate below which SampleWhere stops using rejection sampling and
+samples from the satisfying entries directly instead.
+*/
+const DefaultRejectionThreshold = 0.1
+
+/*
+Get a single random sample from the odds object given that it satisfies the
+condition. Uses DefaultRejectionThreshold to choose how to sample, see
+SampleWhereWithThreshold.
+*/
+func (o *Odds[D, H]) SampleWhere(condition func(*Entry[D, H]) bool, src rand.Source) (*Entry[D, H], error) {
+	return o.SampleWhereWithThreshold(condition, src, DefaultRejectionThreshold)
+}
+
+/*
+Get a single random sample from the odds object given that it satisfies the
+condition. The exact acceptance rate is measured with ConditionWeight. If it is
+at least "threshold", entries are drawn from "o" until one satisfies the
+condition. Otherwise the satisfying entries are sampled once directly. Either
+way entries are visited in hash order, so the draws are reproducible for the
+same source and distribution. Returns ErrZeroProbability if no weight satisfies
+the condition. "o" is not modified. To make repeated conditional draws cheaper,
+use SampleWhere on an AliasSampler or CumulativeSampler built once from "o".
+*/
+func (o *Odds[D, H]) SampleWhereWithThreshold(
+	condition func(*Entry[D, H]) bool,
+	src rand.Source,
+	threshold float64,
+) (*Entry[D, H], error) {
+
+	conditionWeight := o.ConditionWeight(condition)
+	if conditionWeight.Sign() == 0 {
+		return nil, ErrZeroProbability
+	}
+
+	limit := new(big.Rat).SetFloat64(threshold)
+	if limit != nil && o.WeightAsProbability(conditionWeight).Cmp(limit) >= 0 {
+		entries := o.EntriesByHash()
+		for {
+			if entry := selectEntry(entries, randomBelow(src, o.Total)); condition(entry) {
+				return entry, nil
+			}
+		}
+	}
+
+	// Only the satisfying entries, in hash order so the draw is reproducible
+	satisfying := []*Entry[D, H]{}
 	for _, entry := range o.Map {
+		if entry.Weight.Sign() > 0 && condition(entry) {
+			satisfying = append(satisfying, entry)
+		}
+	}
+	sort.Slice(satisfying, func(i, j int) bool {
+		return compareHashes(satisfying[i].Hash, satisfying[j].Hash) < 0
+	})
+
+	return selectEntry(satisfying, randomBelow(src, conditionWeight)), nil
+}
+
+/////////////////////////////
+// SAMPLING W/O REPLACEMENT //
+//////////////////////////////
+
+/*
+Draw "k" distinct entries, each one with probability proportional to its
+weight among the entries not drawn yet. "o" is not modified. Entries are
+visited in hash order, so the draws are reproducible for the same source and
+distribution. Returns an error if "o" has fewer than "k" entries with weight.
+*/
+func (o *Odds[D, H]) SampleWithoutReplacement(k int, src rand.Source) ([]*Entry[D, H], error) {
+	remaining, err := o.entriesForDrawing(k)
+	if err != nil {
+		return nil, err
+	}
+
+	remainingTotal := new(big.Int).Set(o.Total)
+	drawn := make([]*Entry[D, H], 0, k)
+	for len(drawn) < k {
+		point := randomBelow(src, remainingTotal)
+		cumulative := big.NewInt(0)
+		for i, entry := range remaining {
+			cumulative.Add(cumulative, entry.Weight)
+			if cumulative.Cmp(point) > 0 {
+				drawn = append(drawn, entry)
+				remainingTotal.Sub(remainingTotal, entry.Weight)
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return drawn, nil
+}
+
+/*
+Returns the exact distribution of drawing "k" distinct entries from "o" without
+replacement, as in SampleWithoutReplacement. Each outcome holds the data of the
+drawn entries, in the order drawn if "ordered" is true, or sorted by hash
+otherwise, in which case all the orders of the same entries are merged into one
+outcome. Outcomes are hashed by the positions of their entries in hash order,
+so the result only holds data drawn from "o". The number of outcomes grows
+factorially with "k", so this is only practical for small odds.
+*/
+func WithoutReplacement[D any, H comparable](o *Odds[D, H], k int, ordered bool) (*Odds[[]D, string], error) {
+	entries, err := o.entriesForDrawing(k)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[H]int, len(entries))
+	for i, entry := range entries {
+		index[entry.Hash] = i
+	}
+	hashFunction := o.HashFunction
+	positions := func(data []D) []int {
+		p := make([]int, len(data))
+		for i, d := range data {
+			position, exists := index[hashFunction(d)]
+			if !exists {
+				panic("odds: WithoutReplacement outcome holds data not drawn from the original odds")
+			}
+			p[i] = position
+		}
+		return p
+	}
+
+	options := NewOptions(func(data []D) string {
+		p := positions(data)
+		if !ordered {
+			sort.Ints(p)
+		}
+		keys := make([]string, len(p))
+		for i, position := range p {
+			keys[i] = strconv.Itoa(position)
+		}
+		return strings.Join(keys, ",")
+	}).WithDisplay(func(data []D) string {
+		displayed := make([]string, len(data))
+		for i, d := range data {
+			displayed[i] = o.DisplayFunction(d)
+		}
+		return "[" + strings.Join(displayed, ", ") + "]"
+	})
+
+	if o.CopyFunction != nil {
+		options.WithCopy(func(data []D) []D {
+			copied := make([]D, len(data))
+			for i, d := range data {
+				copied[i] = o.CopyFunction(d)
+			}
+			return copied
+		})
+	}
+
+	/*
+		Draw the first entry, then mix together the distributions of the rest
+		of the draws given each possible first entry.
+	*/
+	var draw func(remaining []*Entry[D, H], k int) (*Odds[[]D, string], error)
+	draw = func(remaining []*Entry[D, H], k int) (*Odds[[]D, string], error) {
+		if k == 0 {
+			empty := options.Odds()
+			empty.Add([]D{}, big.NewInt(1))
+			return empty, nil
+		}
+
+		components := make([]*Odds[[]D, string], len(remaining))
+		weights := make([]*big.Int, len(remaining))
+		for i, first := range remaining {
+			rest := append(append([]*Entry[D, H]{}, remaining[:i]...), remaining[i+1:]...)
+			restOdds, err := draw(rest, k-1)
+			if err != nil {
+				return nil, err
+			}
+
+			components[i] = options.Odds()
+			for _, entry := range restOdds.Map {
+				data := append([]D{first.Data}, entry.Data...)
+				if !ordered {
+					p := positions(data)
+					sort.Sort(byPosition[D]{data, p})
+				}
+				components[i].Add(data, new(big.Int).Set(entry.Weight))
+			}
+			weights[i] = first.Weight
+		}
+
+		return Mixture(components, weights)
+	}
+
+	return draw(entries, k)
+}
+
+// Sorts data along with its positions in hash order
+type byPosition[D any] struct {
+	data      []D
+	positions []int
+}
+
+func (b byPosition[D]) Len() int           { return len(b.data) }
+func (b byPosition[D]) Less(i, j int) bool { return b.positions[i] < b.positions[j] }
+func (b byPosition[D]) Swap(i, j int) {
+	b.data[i], b.data[j] = b.data[j], b.data[i]
+	b.positions[i], b.positions[j] = b.positions[j], b.positions[i]
+}
+
+// Entries with weight in hash order, checking that "k" of them can be drawn
+func (o *Odds[D, H]) entriesForDrawing(k int) ([]*Entry[D, H], error) {
+	if k < 0 {
+		return nil, fmt.Errorf("odds: cannot draw %d entries", k)
+	}
+
+	entries := []*Entry[D, H]{}
+	for _, entry := range o.EntriesByHash() {
+		if entry.Weight.Sign() > 0 {
+			entries = append(entries, entry)
+		}
+	}
+
+	if len(entries) < k {
+		return nil, fmt.Errorf("odds: cannot draw %d entries from %d with weight", k, len(entries))
+	}
+	return entries, nil
+}
+
+////////////////////
+// BATCH SAMPLING //
+////////////////////
+
+/*
+Draw "n" samples in parallel across "workers" goroutines. Each worker gets its
+own PCG stream, seeded in order from "src", and draws from a shared
+AliasSampler. The result is the same for the same source, distribution and
+number of workers. Returns nil if the odds have no weight.
+*/
+func (o *Odds[D, H]) SampleN(n, workers int, src rand.Source) []*Entry[D, H] {
+	if o.Total.Sign() <= 0 || n <= 0 {
+		return nil
+	}
+
+	samples := make([]*Entry[D, H], n)
+	o.sampleParallel(n, workers, src, func(_, index int, entry *Entry[D, H]) {
+		samples[index] = entry
+	})
+	return samples
+}
+
+/*
+Draw "n" samples in the same way as SampleN, but tally them into a new odds
+object where the weight of each entry is the number of times it was drawn. The
+samples themselves are never stored, so "n" can be very large.
+*/
+func (o *Odds[D, H]) SampleHistogram(n, workers int, src rand.Source) *Odds[D, H] {
+	histogram := NewOddsFromReference(o)
+	if o.Total.Sign() <= 0 || n <= 0 {
+		return histogram
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	counts := make([]map[H]int64, workers)
+	for i := range counts {
+		counts[i] = map[H]int64{}
+	}
+	o.sampleParallel(n, workers, src, func(worker, _ int, entry *Entry[D, H]) {
+		counts[worker][entry.Hash]++
+	})
+
+	for _, workerCounts := range counts {
+		for hash, count := range workerCounts {
+			histogram.Add(o.Map[hash].Data, big.NewInt(count))
+		}
+	}
+	return histogram
+}
+
+/*
+Splits "n" draws into contiguous blocks, one per worker, and calls "visit" for
+every draw with the worker number and the index of the draw.
+*/
+func (o *Odds[D, H]) sampleParallel(
+	n, workers int,
+	src rand.Source,
+	visit func(worker, index int, entry *Entry[D, H]),
+) {
+	if workers < 1 {
+		workers = 1
+	}
+	sampler := o.AliasSampler()
+	perWorker := (n + workers - 1) / workers
+
+	// Seed every stream up front so the result does not depend on scheduling
+	streams := make([]rand.Source, workers)
+	for i := range streams {
+		streams[i] = rand.NewPCG(src.Uint64(), src.Uint64())
+	}
+
+	done := make(chan bool)
+	for i := 0; i < workers; i++ {
+		go func(worker int) {
+			start := worker * perWorker
+			end := min(start+perWorker, n)
+			for index := start; index < end; index++ {
+				visit(worker, index, sampler.Sample(streams[worker]))
+			}
+			done <- true
+		}(i)
+	}
+
+	for i := 0; i < workers; i++ {
+		<-done
+	}
+}
+
+/////////////
+// HELPERS //
+/////////////
+
+// Finds the entry whose share of the cumulative weight contains "point"
+func selectEntry[D any, H comparable](entries []*Entry[D, H], point *big.Int) *Entry[D, H] {
+	total := big.NewInt(0)
+	for _, entry := range entries {
+		total.Add(total, entry.Weight)
+		if total.Cmp(point) > 0 {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Same as selectEntry, but visits the entries in map order
+func selectFromMap[D any, H comparable](entries map[H]*Entry[D, H], point *big.Int) *Entry[D, H] {
+	total := big.NewInt(0)
+	for _, entry := range entries {
 		total.Add(total, entry.Weight)
-		if total.Cmp(randPoint) >= 0 {
+		if total.Cmp(point) > 0 {
 			return entry
 		}
 	}
 	return nil
 }
+
+// Uniform random integer in [0, max) drawn from "src"
+func randomBelow(src rand.Source, max *big.Int) *big.Int {
+	if max.IsUint64() {
+		return new(big.Int).SetUint64(rand.New(src).Uint64N(max.Uint64()))
+	}
+
+	// Rejection sampling on just enough random bits to cover max
+	bitLen := max.BitLen()
+	b := make([]byte, (bitLen+7)/8)
+	n := new(big.Int)
+	for {
+		for i := 0; i < len(b); i += 8 {
+			var word [8]byte
+			binary.BigEndian.PutUint64(word[:], src.Uint64())
+			copy(b[i:], word[:])
+		}
+		b[0] &= byte(0xFF >> (8*len(b) - bitLen))
+		if n.SetBytes(b).Cmp(max) < 0 {
+			return n
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package odds_test
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/flywingedai/odds"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleWith(t *testing.T) {
+	die := test_Die(6)
+
+	// The same seed gives the same draws, whatever order the map is built in
+	first := []int{}
+	src := rand.NewPCG(1, 2)
+	for i := 0; i < 20; i++ {
+		first = append(first, die.SampleWith(src).Data)
+	}
+
+	reversed := test_Die(0)
+	for i := 6; i >= 1; i-- {
+		reversed.Add(i, big.NewInt(1))
+	}
+	src = rand.NewPCG(1, 2)
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, first[i], reversed.SampleWith(src).Data)
+	}
+
+	// Weights far beyond 64 bits are sampled exactly
+	huge := new(big.Int).Lsh(big.NewInt(1), 200)
+	lopsided := test_Die(0)
+	lopsided.Add(1, big.NewInt(1))
+	lopsided.Add(2, huge)
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, 2, lopsided.SampleWith(src).Data)
+	}
+
+	assert.Nil(t, test_Die(0).SampleWith(src))
+	assert.NotNil(t, die.Sample())
+	assert.Nil(t, test_Die(0).Sample())
+}
+
+func TestMixedHashOrder(t *testing.T) {
+	mixed := odds.NewOptions(func(d any) any { return d }).Odds()
+	mixed.Add(1, big.NewInt(1))
+	mixed.Add("x", big.NewInt(1))
+	mixed.Add(2, big.NewInt(1))
+
+	// Hashes of different types are ordered by type name, then by value
+	entries := mixed.EntriesByHash()
+	assert.Equal(t, []any{1, 2, "x"}, []any{entries[0].Hash, entries[1].Hash, entries[2].Hash})
+	assert.NotNil(t, mixed.SampleWith(rand.NewPCG(1, 2)))
+}
+
+func TestSampleFrom(t *testing.T) {
+	// A stream of zero bytes always selects the lowest hash
+	entry, err := test_Die(6).SampleFrom(zeroReader{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, entry.Data)
+}
+
+// Test Functions //
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
+
+func TestSamplers(t *testing.T) {
+	weighted := test_Die(0)
+	for i := 1; i <= 10; i++ {
+		weighted.Add(i, big.NewInt(int64(i*i)))
+	}
+	huge := test_Die(0)
+	for i := 1; i <= 10; i++ {
+		huge.Add(i, new(big.Int).Lsh(big.NewInt(int64(i*i)), 100))
+	}
+
+	for _, o := range []*odds.Odds[int, int]{weighted, huge} {
+		for _, sampler := range []odds.Sampler[int, int]{o.AliasSampler(), o.CumulativeSampler()} {
+			src := rand.NewPCG(3, 4)
+			histogram := o.AsReference()
+			for i := 0; i < 100_000; i++ {
+				histogram.Add(sampler.Sample(src).Data, big.NewInt(1))
+			}
+			assert.True(t, odds.ApproxEqual(o, histogram, 0.01))
+		}
+	}
+
+	assert.Nil(t, test_Die(0).AliasSampler().Sample(rand.NewPCG(1, 1)))
+	assert.Nil(t, test_Die(0).CumulativeSampler().Sample(rand.NewPCG(1, 1)))
+}
+
+func TestSampleN(t *testing.T) {
+	die := test_Die(6)
+
+	samples := die.SampleN(1000, 4, rand.NewPCG(5, 6))
+	assert.Equal(t, 1000, len(samples))
+	again := die.SampleN(1000, 4, rand.NewPCG(5, 6))
+	for i := range samples {
+		assert.Equal(t, samples[i].Data, again[i].Data)
+	}
+
+	histogram := die.SampleHistogram(600_000, 8, rand.NewPCG(7, 8))
+	assert.Equal(t, big.NewInt(600_000), histogram.Total)
+	assert.True(t, odds.ApproxEqual(die, histogram, 0.01))
+
+	assert.Nil(t, test_Die(0).SampleN(10, 2, rand.NewPCG(1, 1)))
+	assert.Equal(t, 0, test_Die(0).SampleHistogram(10, 2, rand.NewPCG(1, 1)).Total.Sign())
+}
+
+func TestWithoutReplacement(t *testing.T) {
+	weighted := test_Die(0)
+	for i := 1; i <= 3; i++ {
+		weighted.Add(i, big.NewInt(int64(i)))
+	}
+
+	ordered, err := odds.WithoutReplacement(weighted, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(ordered.Map))
+	assert.Equal(t, big.NewRat(1, 15), ordered.Probability([]int{1, 2}))
+	assert.Equal(t, big.NewRat(1, 12), ordered.Probability([]int{2, 1}))
+	assert.Equal(t, "[2, 1]", ordered.DisplayFunction([]int{2, 1}))
+
+	unordered, err := odds.WithoutReplacement(weighted, 2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(unordered.Map))
+	assert.Equal(t, big.NewRat(3, 20), unordered.Probability([]int{2, 1}))
+
+	// Draws are distinct, reproducible and leave the odds untouched
+	src := rand.NewPCG(9, 10)
+	for i := 0; i < 100; i++ {
+		drawn, err := weighted.SampleWithoutReplacement(3, src)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []int{1, 2, 3}, []int{drawn[0].Data, drawn[1].Data, drawn[2].Data})
+	}
+	assert.Equal(t, big.NewInt(6), weighted.Total)
+	assert.Equal(t, 3, len(weighted.Map))
+
+	_, err = weighted.SampleWithoutReplacement(4, src)
+	assert.Error(t, err)
+	_, err = odds.WithoutReplacement(weighted, 4, true)
+	assert.Error(t, err)
+
+	// Hashes which print the same are still separate outcomes
+	alike := odds.NewOptions(func(d any) any { return d }).Odds()
+	alike.Add(1, big.NewInt(1))
+	alike.Add("1", big.NewInt(1))
+	alike.Add(2, big.NewInt(1))
+	pairs, err := odds.WithoutReplacement(alike, 2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(pairs.Map))
+	assert.Equal(t, big.NewRat(1, 6), pairs.Probability([]any{1, "1"}))
+}
+
+func TestSampleWhere(t *testing.T) {
+	die := test_Die(100)
+	src := rand.NewPCG(11, 12)
+
+	// Likely events use rejection, unlikely ones a table of only the
+	// satisfying entries, and prebuilt samplers always use rejection
+	lowest := func(e *odds.Entry[int, int]) bool { return e.Data <= 4 }
+	alias, cumulative := die.AliasSampler(), die.CumulativeSampler()
+	for _, sample := range []func() (*odds.Entry[int, int], error){
+		func() (*odds.Entry[int, int], error) { return die.SampleWhereWithThreshold(lowest, src, 0) },
+		func() (*odds.Entry[int, int], error) { return die.SampleWhereWithThreshold(lowest, src, 1) },
+		func() (*odds.Entry[int, int], error) { return alias.SampleWhere(lowest, src) },
+		func() (*odds.Entry[int, int], error) { return cumulative.SampleWhere(lowest, src) },
+	} {
+		histogram := die.AsReference()
+		for i := 0; i < 20_000; i++ {
+			entry, err := sample()
+			assert.NoError(t, err)
+			histogram.Add(entry.Data, big.NewInt(1))
+		}
+		assert.True(t, odds.ApproxEqual(test_Die(4), histogram, 0.02))
+	}
+
+	// The same seed gives the same draws, whatever order the map is built in
+	reversed := test_Die(0)
+	for i := 100; i >= 1; i-- {
+		reversed.Add(i, big.NewInt(1))
+	}
+	for _, threshold := range []float64{0, 1} {
+		first, _ := die.SampleWhereWithThreshold(lowest, rand.NewPCG(3, 4), threshold)
+		second, _ := reversed.SampleWhereWithThreshold(lowest, rand.NewPCG(3, 4), threshold)
+		assert.Equal(t, first.Data, second.Data)
+	}
+
+	_, err := alias.SampleWhere(func(e *odds.Entry[int, int]) bool { return e.Data > 100 }, src)
+	assert.ErrorIs(t, err, odds.ErrZeroProbability)
+
+	entry, err := die.SampleWhere(func(e *odds.Entry[int, int]) bool { return e.Data == 50 }, src)
+	assert.NoError(t, err)
+	assert.Same(t, die.Map[50], entry)
+
+	_, err = die.SampleWhere(func(e *odds.Entry[int, int]) bool { return e.Data > 100 }, src)
+	assert.ErrorIs(t, err, odds.ErrZeroProbability)
+}
@@ -0,0 +1,229 @@
+package odds
+
+import (
+	"math/big"
+	"math/rand/v2"
+	"sort"
+)
+
+/*
+Draws repeated samples from a snapshot of an odds object. The snapshot is taken
+when the sampler is built, so later changes to the odds are not reflected in
+it. Samplers are safe to share between goroutines as long as each goroutine
+uses its own source.
+*/
+type Sampler[D any, H comparable] interface {
+	Sample(src rand.Source) *Entry[D, H]
+}
+
+///////////////////
+// ALIAS SAMPLER //
+///////////////////
+
+/*
+Sampler built on Vose's alias method. Building takes O(n) big.Int operations
+after sorting the entries, and every draw afterwards takes constant time: one
+column is picked uniformly, and a second uniform draw decides between the
+column's entry and its alias. All thresholds are exact integers out of the total
+weight, so no probability is rounded.
+*/
+type AliasSampler[D any, H comparable] struct {
+	entries    []*Entry[D, H]
+	aliases    []int
+	thresholds []*big.Int
+	total      *big.Int
+
+	// Copies of the thresholds and total, used when the total fits in 64 bits
+	smallThresholds []uint64
+	smallTotal      uint64
+}
+
+/*
+Build an AliasSampler for "o". Entries are laid out in hash order, so draws are
+reproducible for the same source and distribution.
+*/
+func (o *Odds[D, H]) AliasSampler() *AliasSampler[D, H] {
+	entries := []*Entry[D, H]{}
+	for _, entry := range o.EntriesByHash() {
+		if entry.Weight.Sign() > 0 {
+			entries = append(entries, entry)
+		}
+	}
+
+	n := len(entries)
+	sampler := &AliasSampler[D, H]{
+		entries:    entries,
+		aliases:    make([]int, n),
+		thresholds: make([]*big.Int, n),
+		total:      new(big.Int).Set(o.Total),
+	}
+
+	/*
+		Each column holds total / n of the weight, so scale every weight by n
+		and compare against the total. Columns under the total are topped up
+		by the alias of a column over it.
+	*/
+	scaled := make([]*big.Int, n)
+	small, large := []int{}, []int{}
+	nBig := big.NewInt(int64(n))
+	for i, entry := range entries {
+		scaled[i] = new(big.Int).Mul(entry.Weight, nBig)
+		if scaled[i].Cmp(sampler.total) < 0 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	for len(small) > 0 && len(large) > 0 {
+		l := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		sampler.thresholds[l] = scaled[l]
+		sampler.aliases[l] = g
+
+		scaled[g].Add(scaled[g], scaled[l])
+		scaled[g].Sub(scaled[g], sampler.total)
+		if scaled[g].Cmp(sampler.total) < 0 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+
+	// Whatever is left is exactly full
+	for _, i := range append(small, large...) {
+		sampler.thresholds[i] = sampler.total
+		sampler.aliases[i] = i
+	}
+
+	if sampler.total.IsUint64() {
+		sampler.smallTotal = sampler.total.Uint64()
+		sampler.smallThresholds = make([]uint64, n)
+		for i, threshold := range sampler.thresholds {
+			sampler.smallThresholds[i] = threshold.Uint64()
+		}
+	}
+
+	return sampler
+}
+
+// Draw a single entry in constant time. Returns nil if there is no weight.
+func (s *AliasSampler[D, H]) Sample(src rand.Source) *Entry[D, H] {
+	if len(s.entries) == 0 {
+		return nil
+	}
+
+	r := rand.New(src)
+	column := r.IntN(len(s.entries))
+
+	if s.smallThresholds != nil {
+		if r.Uint64N(s.smallTotal) < s.smallThresholds[column] {
+			return s.entries[column]
+		}
+		return s.entries[s.aliases[column]]
+	}
+
+	if randomBelow(src, s.total).Cmp(s.thresholds[column]) < 0 {
+		return s.entries[column]
+	}
+	return s.entries[s.aliases[column]]
+}
+
+/*
+Draw entries until one satisfies the condition. Returns ErrZeroProbability if
+no entry with weight satisfies it. Best suited to likely events, since every
+rejected draw is wasted.
+*/
+func (s *AliasSampler[D, H]) SampleWhere(condition func(*Entry[D, H]) bool, src rand.Source) (*Entry[D, H], error) {
+	return sampleWhere(s, s.entries, condition, src)
+}
+
+////////////////////////
+// CUMULATIVE SAMPLER //
+////////////////////////
+
+/*
+Sampler which binary searches a table of cumulative weights. It is cheaper to
+build than an AliasSampler, at the cost of O(log n) draws.
+*/
+type CumulativeSampler[D any, H comparable] struct {
+	entries    []*Entry[D, H]
+	cumulative []*big.Int
+	total      *big.Int
+}
+
+/*
+Build a CumulativeSampler for "o". Entries are laid out in hash order, so draws
+are reproducible for the same source and distribution.
+*/
+func (o *Odds[D, H]) CumulativeSampler() *CumulativeSampler[D, H] {
+	entries := o.EntriesByHash()
+	sampler := &CumulativeSampler[D, H]{
+		entries:    entries,
+		cumulative: make([]*big.Int, len(entries)),
+		total:      new(big.Int).Set(o.Total),
+	}
+
+	running := big.NewInt(0)
+	for i, entry := range entries {
+		running.Add(running, entry.Weight)
+		sampler.cumulative[i] = new(big.Int).Set(running)
+	}
+
+	return sampler
+}
+
+// Draw a single entry in O(log n) time. Returns nil if there is no weight.
+func (s *CumulativeSampler[D, H]) Sample(src rand.Source) *Entry[D, H] {
+	if s.total.Sign() <= 0 {
+		return nil
+	}
+
+	point := randomBelow(src, s.total)
+	i := sort.Search(len(s.cumulative), func(i int) bool {
+		return s.cumulative[i].Cmp(point) > 0
+	})
+	return s.entries[i]
+}
+
+/*
+Draw entries until one satisfies the condition. Returns ErrZeroProbability if
+no entry with weight satisfies it. Best suited to likely events, since every
+rejected draw is wasted.
+*/
+func (s *CumulativeSampler[D, H]) SampleWhere(condition func(*Entry[D, H]) bool, src rand.Source) (*Entry[D, H], error) {
+	return sampleWhere(s, s.entries, condition, src)
+}
+
+/////////////
+// HELPERS //
+/////////////
+
+// Rejection sampling from "sampler", after checking some entry can be accepted
+func sampleWhere[D any, H comparable](
+	sampler Sampler[D, H],
+	entries []*Entry[D, H],
+	condition func(*Entry[D, H]) bool,
+	src rand.Source,
+) (*Entry[D, H], error) {
+
+	possible := false
+	for _, entry := range entries {
+		if entry.Weight.Sign() > 0 && condition(entry) {
+			possible = true
+			break
+		}
+	}
+	if !possible {
+		return nil, ErrZeroProbability
+	}
+
+	for {
+		if entry := sampler.Sample(src); condition(entry) {
+			return entry, nil
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package odds_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/flywingedai/odds"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	options := test_SerializeOptions()
+
+	original := options.Odds()
+	for i := 1; i <= 5; i++ {
+		original.Add(i, big.NewInt(int64(i*i)))
+	}
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	original.Add(6, huge)
+
+	b, err := json.Marshal(original)
+	assert.NoError(t, err)
+
+	decoded := options.Odds()
+	assert.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, original.Total, decoded.Total)
+	assert.Equal(t, len(original.Map), len(decoded.Map))
+	for hash, entry := range original.Map {
+		assert.Equal(t, entry.Weight, decoded.Map[hash].Weight)
+		assert.Equal(t, entry.Data, decoded.Map[hash].Data)
+	}
+
+	// A mismatched total is reported rather than silently accepted
+	err = decoded.UnmarshalJSON([]byte(`{"total":"3","entries":[{"data":1,"weight":"2"}]}`))
+	assert.Error(t, err)
+
+	// Failures leave the existing entries in place
+	err = decoded.UnmarshalJSON([]byte(`{"total":"2","entries":[{"data":1,"weight":"2"},{"data":2,"weight":"x"}]}`))
+	assert.Error(t, err)
+	assert.Equal(t, original.Total, decoded.Total)
+	assert.Equal(t, len(original.Map), len(decoded.Map))
+}
+
+func TestBinary(t *testing.T) {
+	options := test_SerializeOptions()
+
+	original := options.Odds()
+	for i := 1; i <= 100; i++ {
+		original.Add(i, big.NewInt(int64(i*i*i)))
+	}
+
+	buffer := &bytes.Buffer{}
+	written, err := original.WriteTo(buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buffer.Len()), written)
+	snapshot := buffer.Bytes()
+
+	decoded := options.Odds()
+	read, err := decoded.ReadFrom(bytes.NewReader(snapshot))
+	assert.NoError(t, err)
+	assert.Equal(t, written, read)
+	assert.Equal(t, original.Total, decoded.Total)
+	for hash, entry := range original.Map {
+		assert.Equal(t, entry.Weight, decoded.Map[hash].Weight)
+	}
+
+	// Truncated snapshots are rejected
+	_, err = options.Odds().ReadFrom(bytes.NewReader(snapshot[:len(snapshot)-3]))
+	assert.Error(t, err)
+
+	// Flipped bits are caught by the checksum
+	corrupted := bytes.Clone(snapshot)
+	corrupted[len(corrupted)/2] ^= 0xFF
+	_, err = options.Odds().ReadFrom(bytes.NewReader(corrupted))
+	assert.Error(t, err)
+
+	// Failures leave the existing entries in place
+	_, err = decoded.ReadFrom(bytes.NewReader(snapshot[:len(snapshot)-3]))
+	assert.Error(t, err)
+	assert.Equal(t, original.Total, decoded.Total)
+	assert.Equal(t, len(original.Map), len(decoded.Map))
+}
+
+func TestStream(t *testing.T) {
+	options := test_SerializeOptions()
+
+	original := options.Odds()
+	for i := 1; i <= 10; i++ {
+		original.Add(i, big.NewInt(int64(i)))
+	}
+	buffer := &bytes.Buffer{}
+	_, err := original.WriteTo(buffer)
+	assert.NoError(t, err)
+
+	// Filter the even entries straight into a new snapshot in a single pass
+	decoder, err := odds.NewDecoder(bytes.NewReader(buffer.Bytes()), options.Odds())
+	assert.NoError(t, err)
+	filtered := &bytes.Buffer{}
+	encoder, err := odds.NewEncoder(filtered, original)
+	assert.NoError(t, err)
+	for {
+		entry, err := decoder.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if entry.Data%2 == 0 {
+			assert.NoError(t, encoder.Encode(entry))
+		}
+	}
+	assert.Equal(t, uint64(10), decoder.Count())
+	assert.NoError(t, encoder.Close())
+
+	// Load only part of the filtered snapshot
+	even := options.Odds()
+	_, err = even.ReadEach(filtered, func(e *odds.Entry[int, int]) error {
+		if e.Data > 4 {
+			even.AddEntry(e)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(even.Map))
+	assert.Equal(t, big.NewInt(24), even.Total)
+
+}
+
+func TestCSV(t *testing.T) {
+	options := test_SerializeOptions()
+
+	original := options.Odds()
+	original.Add(1, big.NewInt(1))
+	original.Add(2, big.NewInt(3))
+
+	buffer := &bytes.Buffer{}
+	assert.NoError(t, original.ExportCSV(buffer, nil))
+	assert.Equal(t, "outcome,weight,probability,percent\n"+
+		"1,1,1/4,25.0000000000\n"+
+		"2,3,3/4,75.0000000000\n", buffer.String())
+
+	imported := options.Odds()
+	assert.NoError(t, imported.ImportCSV(buffer, strconv.Atoi))
+	assert.Equal(t, original.Total, imported.Total)
+	assert.Equal(t, big.NewInt(3), imported.Map[2].Weight)
+
+	err := options.Odds().ImportCSV(strings.NewReader("outcome,weight\nx,1\n"), strconv.Atoi)
+	assert.Error(t, err)
+
+	// Rows before a bad one are not imported
+	err = imported.ImportCSV(strings.NewReader("outcome,weight\n5,1\n6,x\n"), strconv.Atoi)
+	assert.Error(t, err)
+	assert.Equal(t, original.Total, imported.Total)
+	assert.Nil(t, imported.Map[5])
+}
+
+func TestRegistry(t *testing.T) {
+	options := test_SerializeOptions().WithDisplay(func(i int) string { return "#" + strconv.Itoa(i) })
+	odds.Register("registry-test", options)
+	t.Cleanup(func() { odds.Unregister("registry-test") })
+	assert.Panics(t, func() { odds.Register("registry-test", options) })
+	assert.Panics(t, func() { odds.Register("registry-test-no-decode", odds.NewOptions(func(i int) int { return i })) })
+
+	// Later changes to the options do not affect what is registered
+	options.WithDisplay(func(i int) string { return "changed" })
+	registered, err := odds.Lookup[int, int]("registry-test")
+	assert.NoError(t, err)
+	assert.Equal(t, "#3", registered.DisplayFunction(3))
+
+	original := options.Odds()
+	original.Add(1, big.NewInt(2))
+	original.Add(2, big.NewInt(5))
+
+	b, err := json.Marshal(original)
+	assert.NoError(t, err)
+	fromJSON, err := odds.LoadJSON[int, int](b)
+	assert.NoError(t, err)
+	assert.Equal(t, "registry-test", fromJSON.TypeName)
+	assert.Equal(t, "#2", fromJSON.DisplayFunction(2))
+	assert.Equal(t, big.NewInt(7), fromJSON.Total)
+
+	buffer := &bytes.Buffer{}
+	_, err = original.WriteTo(buffer)
+	assert.NoError(t, err)
+	fromBinary, err := odds.Load[int, int](buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, "#1", fromBinary.DisplayFunction(1))
+	assert.Equal(t, big.NewInt(5), fromBinary.Map[2].Weight)
+
+	// Unknown names and mismatched types are errors
+	_, err = odds.LoadJSON[int, int]([]byte(`{"type":"missing","total":"0","entries":[]}`))
+	assert.ErrorIs(t, err, odds.ErrUnknownType)
+	_, err = odds.LoadJSON[string, string](b)
+	assert.ErrorIs(t, err, odds.ErrUnknownType)
+}
+
+// Test Functions //
+
+func test_SerializeOptions() *odds.OddsOptions[int, int] {
+	return odds.NewOptions(func(i int) int { return i }).
+		WithCopy(func(i int) int { return i }).
+		WithEncode(odds.JSONEncode[int]).
+		WithDecode(odds.JSONDecode[int])
+}
@@ -0,0 +1,130 @@
+package odds
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+var ErrZeroTotal = errors.New("odds: total weight is zero")
+
+// Data types which the integer convenience functions can be used with
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Converts integer data to an exact rational value
+func IntegerValue[D Integer](data D) *big.Rat {
+	if data < 0 {
+		return new(big.Rat).SetInt64(int64(data))
+	}
+	return new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(data)))
+}
+
+/////////////
+// MOMENTS //
+/////////////
+
+/*
+Returns the exact expected value of "value" over the entries of "o".
+*/
+func Expectation[D any, H comparable](o *Odds[D, H], value func(D) *big.Rat) (*big.Rat, error) {
+	return Moment(o, value, 1)
+}
+
+/*
+Returns the exact k-th raw moment E[X^k] of "value" over the entries of "o".
+*/
+func Moment[D any, H comparable](o *Odds[D, H], value func(D) *big.Rat, k int) (*big.Rat, error) {
+	return moment(o, value, k, new(big.Rat))
+}
+
+/*
+Returns the exact k-th central moment E[(X - E[X])^k] of "value" over the
+entries of "o".
+*/
+func CentralMoment[D any, H comparable](o *Odds[D, H], value func(D) *big.Rat, k int) (*big.Rat, error) {
+	mean, err := Expectation(o, value)
+	if err != nil {
+		return nil, err
+	}
+	return moment(o, value, k, mean)
+}
+
+/*
+Returns the exact variance of "value" over the entries of "o".
+*/
+func Variance[D any, H comparable](o *Odds[D, H], value func(D) *big.Rat) (*big.Rat, error) {
+	return CentralMoment(o, value, 2)
+}
+
+/*
+Returns the standard deviation of "value" over the entries of "o". Since square
+roots are generally irrational, this is the only moment which is not exact.
+*/
+func StdDev[D any, H comparable](o *Odds[D, H], value func(D) *big.Rat) (float64, error) {
+	variance, err := Variance(o, value)
+	if err != nil {
+		return 0, err
+	}
+	f, _ := variance.Float64()
+	return math.Sqrt(f), nil
+}
+
+/*
+Sum of weight * (value - center)^k over all entries, divided by the total. All
+the weighted terms are accumulated before the single division.
+*/
+func moment[D any, H comparable](o *Odds[D, H], value func(D) *big.Rat, k int, center *big.Rat) (*big.Rat, error) {
+	if k < 0 {
+		return nil, fmt.Errorf("odds: negative moment %d", k)
+	}
+	if o.Total.Sign() == 0 {
+		return nil, ErrZeroTotal
+	}
+
+	sum := new(big.Rat)
+	term := new(big.Rat)
+	for _, entry := range o.Map {
+		x := new(big.Rat).Sub(value(entry.Data), center)
+		power := big.NewRat(1, 1)
+		for i := 0; i < k; i++ {
+			power.Mul(power, x)
+		}
+		term.SetInt(entry.Weight)
+		sum.Add(sum, term.Mul(term, power))
+	}
+
+	return sum.Quo(sum, new(big.Rat).SetInt(o.Total)), nil
+}
+
+/////////////////////
+// INTEGER MOMENTS //
+/////////////////////
+
+// Expectation of odds whose data is an integer
+func ExpectationInt[D Integer, H comparable](o *Odds[D, H]) (*big.Rat, error) {
+	return Expectation(o, IntegerValue[D])
+}
+
+// Moment of odds whose data is an integer
+func MomentInt[D Integer, H comparable](o *Odds[D, H], k int) (*big.Rat, error) {
+	return Moment(o, IntegerValue[D], k)
+}
+
+// CentralMoment of odds whose data is an integer
+func CentralMomentInt[D Integer, H comparable](o *Odds[D, H], k int) (*big.Rat, error) {
+	return CentralMoment(o, IntegerValue[D], k)
+}
+
+// Variance of odds whose data is an integer
+func VarianceInt[D Integer, H comparable](o *Odds[D, H]) (*big.Rat, error) {
+	return Variance(o, IntegerValue[D])
+}
+
+// StdDev of odds whose data is an integer
+func StdDevInt[D Integer, H comparable](o *Odds[D, H]) (float64, error) {
+	return StdDev(o, IntegerValue[D])
+}
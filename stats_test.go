@@ -0,0 +1,186 @@
+package odds_test
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/flywingedai/odds"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoments(t *testing.T) {
+	die := test_Die(6)
+
+	mean, err := odds.ExpectationInt(die)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(7, 2), mean)
+
+	variance, err := odds.VarianceInt(die)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(35, 12), variance)
+
+	stdDev, err := odds.StdDevInt(die)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(35.0/12.0), stdDev, 1e-12)
+
+	second, err := odds.MomentInt(die, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(91, 6), second)
+
+	// A symmetric distribution has no skew
+	third, err := odds.CentralMomentInt(die, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, third.Sign())
+
+	_, err = odds.ExpectationInt(die.AsReference())
+	assert.ErrorIs(t, err, odds.ErrZeroTotal)
+}
+
+func TestCDF(t *testing.T) {
+	sums := odds.Marginalize(odds.Product(test_Die(6), test_Die(6)), func(p odds.Pair[int, int]) int {
+		return p.First + p.Second
+	})
+	cdf := sums.CDF(func(a, b int) bool { return a < b })
+
+	assert.Equal(t, 11, len(cdf.Entries))
+	assert.Equal(t, 2, cdf.Entries[0].Entry.Data)
+	assert.Equal(t, big.NewRat(1, 36), cdf.Entries[0].Cumulative)
+	assert.Equal(t, big.NewRat(1, 1), cdf.Entries[10].Cumulative)
+
+	median, err := cdf.Median()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, median.Data)
+
+	// P(sum <= 10) = 33/36 < 95% <= P(sum <= 11) = 35/36
+	p95, err := cdf.Percentile(95)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, p95.Data)
+
+	// Exact boundaries land on the entry that reaches them
+	q, err := cdf.Quantile(big.NewRat(6, 36))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, q.Data)
+
+	_, err = cdf.Quantile(big.NewRat(3, 2))
+	assert.Error(t, err)
+}
+
+func TestInformation(t *testing.T) {
+	d4, d8 := test_Die(4), test_Die(8)
+
+	entropy, err := odds.Entropy(d8)
+	assert.NoError(t, err)
+	assert.InDelta(t, 3, entropy, 1e-12)
+
+	// d4 is covered by d8, but not the other way around
+	divergence, err := odds.KLDivergence(d4, d8)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, divergence, 1e-12)
+	divergence, err = odds.KLDivergence(d8, d4)
+	assert.NoError(t, err)
+	assert.True(t, math.IsInf(divergence, 1))
+
+	crossEntropy, err := odds.CrossEntropy(d4, d8)
+	assert.NoError(t, err)
+	assert.InDelta(t, 3, crossEntropy, 1e-12)
+
+	// Half of d8 overlaps d4: JS = 1/2 * KL(d4 || m) + 1/2 * KL(d8 || m)
+	js, err := odds.JensenShannon(d4, d8)
+	assert.NoError(t, err)
+	reverse, _ := odds.JensenShannon(d8, d4)
+	assert.InDelta(t, js, reverse, 1e-12)
+	assert.InDelta(t, 0.5*math.Log2(4.0/3.0)+0.25*math.Log2(2.0/3.0)+0.25, js, 1e-12)
+
+	// Independent dice share no information, a die shares all of it with itself
+	joint := odds.Product(d4, d8)
+	information, err := odds.MutualInformation(joint, func(p odds.Pair[int, int]) (int, int) {
+		return p.First, p.Second
+	})
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, information, 1e-12)
+	information, err = odds.MutualInformation(d8, func(i int) (int, bool) { return i, i%2 == 0 })
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, information, 1e-12)
+}
+
+func TestDistances(t *testing.T) {
+	d4, d6 := test_Die(4), test_Die(6)
+
+	tv, err := odds.TotalVariation(d4, d6)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 3), tv)
+	tv, err = odds.TotalVariation(d4, test_Die(4).Scale(big.NewInt(7)))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tv.Sign())
+
+	hellinger, err := odds.Hellinger(d4, d6)
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(1-4*math.Sqrt(1.0/24.0)), hellinger, 1e-12)
+
+	// Shifting a die by one moves every outcome a distance of 1
+	shifted := odds.Map(d6, func(i int) int { return i + 1 }, odds.NewOptions(func(i int) int { return i }))
+	w1, err := odds.Wasserstein1(d6, shifted, odds.IntegerValue[int])
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 1), w1)
+
+	ks, err := odds.KolmogorovSmirnov(d6, shifted, odds.IntegerValue[int])
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 6), ks)
+
+	w1, err = odds.Wasserstein1(d4, d6, odds.IntegerValue[int])
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 1), w1)
+}
+
+func TestDiff(t *testing.T) {
+	d4, d6 := test_Die(4), test_Die(6)
+
+	report := odds.Diff(d4, d6)
+	assert.Equal(t, 2, len(report.Added))
+	assert.Equal(t, 0, len(report.Removed))
+	assert.Equal(t, 4, len(report.Reweighted))
+	assert.Equal(t, big.NewRat(-1, 12), report.Reweighted[0].Change())
+	assert.Equal(t, ""+
+		"+ 5: 0 -> 1/6 (+1/6)\n"+
+		"+ 6: 0 -> 1/6 (+1/6)\n"+
+		"~ 1: 1/4 -> 1/6 (-1/12)\n"+
+		"~ 2: 1/4 -> 1/6 (-1/12)\n"+
+		"~ 3: 1/4 -> 1/6 (-1/12)\n"+
+		"~ 4: 1/4 -> 1/6 (-1/12)", report.String())
+
+	// Raw weights do not matter, only probabilities
+	assert.True(t, odds.Diff(d6, test_Die(6).Scale(big.NewInt(5))).Empty())
+}
+
+func TestEqual(t *testing.T) {
+	d6 := test_Die(6)
+	scaled := test_Die(6).Scale(big.NewInt(3))
+
+	assert.True(t, odds.Equal(d6, scaled))
+	assert.False(t, odds.Equal(d6, test_Die(5)))
+	assert.True(t, odds.Equal(d6.AsReference(), scaled.AsReference()))
+
+	// Moving 1/600 of the probability between two outcomes
+	nudged := test_Die(6).Scale(big.NewInt(100))
+	nudged.Map[1].Weight.Add(nudged.Map[1].Weight, big.NewInt(1))
+	nudged.Map[2].Weight.Sub(nudged.Map[2].Weight, big.NewInt(1))
+	assert.False(t, odds.Equal(d6, nudged))
+	assert.True(t, odds.ApproxEqual(d6, nudged, 0.002))
+	assert.False(t, odds.ApproxEqual(d6, nudged, 0.001))
+}
+
+func TestFingerprint(t *testing.T) {
+	encode := func(i int) []byte { return []byte(strconv.Itoa(i)) }
+
+	d6 := test_Die(6)
+	fingerprint := d6.Fingerprint(encode)
+	assert.Equal(t, fingerprint, test_Die(6).Scale(big.NewInt(12)).Fingerprint(encode))
+	assert.Equal(t, fingerprint, test_Die(6).Scale(big.NewInt(12)).Reduce().Fingerprint(encode))
+	assert.NotEqual(t, fingerprint, test_Die(5).Fingerprint(encode))
+
+	d6.Map[6].Weight.SetInt64(2)
+	d6.Total.SetInt64(7)
+	assert.NotEqual(t, fingerprint, d6.Fingerprint(encode))
+}
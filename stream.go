@@ -0,0 +1,314 @@
+package odds
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+/////////////
+// ENCODER //
+/////////////
+
+/*
+Writes entries to a binary snapshot one at a time, so odds can be saved without
+building any intermediate slice of entries. The number of entries is written
+after the last one, so it does not need to be known up front.
+*/
+type Encoder[D any, H comparable] struct {
+	bw             *binaryWriter
+	encodeFunction func(D) ([]byte, error)
+
+	written uint64
+	total   *big.Int
+	closed  bool
+}
+
+/*
+Create a new Encoder which writes a snapshot to "w". The EncodeFunction of
+"reference" is used to encode each entry.Data, and its TypeName is stored in
+the snapshot. The snapshot header is written immediately.
+*/
+func NewEncoder[D any, H comparable](w io.Writer, reference *Odds[D, H]) (*Encoder[D, H], error) {
+	if reference.EncodeFunction == nil {
+		return nil, fmt.Errorf("odds: no EncodeFunction provided")
+	}
+
+	encoder := &Encoder[D, H]{
+		bw:             newBinaryWriter(w),
+		encodeFunction: reference.EncodeFunction,
+		total:          big.NewInt(0),
+	}
+
+	if err := encoder.bw.write(append([]byte(binaryMagic), binaryVersion)); err != nil {
+		return nil, err
+	}
+	if err := encoder.bw.writeBytes([]byte(reference.TypeName)); err != nil {
+		return nil, err
+	}
+
+	return encoder, nil
+}
+
+// Write a single entry to the snapshot.
+func (e *Encoder[D, H]) Encode(entry *Entry[D, H]) error {
+	if e.closed {
+		return fmt.Errorf("odds: encoder is closed")
+	}
+	if entry.Weight.Sign() < 0 {
+		return fmt.Errorf("odds: cannot encode negative weight %s", entry.Weight)
+	}
+
+	data, err := e.encodeFunction(entry.Data)
+	if err != nil {
+		return fmt.Errorf("odds: encoding data: %w", err)
+	}
+	if err := e.bw.write([]byte{binaryEntryMarker}); err != nil {
+		return err
+	}
+	if err := e.bw.writeBytes(entry.Weight.Bytes()); err != nil {
+		return err
+	}
+	if err := e.bw.writeBytes(data); err != nil {
+		return err
+	}
+
+	e.written++
+	e.total.Add(e.total, entry.Weight)
+	return nil
+}
+
+/*
+Finish the snapshot by writing the number of entries, the total and the
+checksum. Does not close the underlying writer.
+*/
+func (e *Encoder[D, H]) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	if err := e.bw.write([]byte{binaryEndMarker}); err != nil {
+		return err
+	}
+	if err := e.bw.writeUvarint(e.written); err != nil {
+		return err
+	}
+	if err := e.bw.writeBytes(e.total.Bytes()); err != nil {
+		return err
+	}
+	return e.bw.writeChecksum()
+}
+
+// Number of bytes written to the underlying writer so far
+func (e *Encoder[D, H]) BytesWritten() int64 {
+	return e.bw.n
+}
+
+/////////////
+// DECODER //
+/////////////
+
+/*
+Reads entries from a binary snapshot one at a time, so snapshots can be loaded,
+filtered or re-encoded without ever holding the whole odds in memory.
+*/
+type Decoder[D any, H comparable] struct {
+	br             *binaryReader
+	hashFunction   func(D) H
+	decodeFunction func([]byte) (D, error)
+
+	// Options found in the registry when no usable reference was given
+	options *OddsOptions[D, H]
+
+	typeName string
+	read     uint64
+	total    *big.Int
+	err      error
+}
+
+/*
+Create a new Decoder which reads a snapshot from "r". The HashFunction and
+DecodeFunction of "reference" are used to rebuild each entry. If "reference" is
+nil or has no DecodeFunction, the options registered under the type name stored
+in the snapshot are used instead. The snapshot header is read immediately.
+
+If "r" is not an io.ByteReader it is buffered, so bytes past the end of the
+snapshot may be consumed from "r".
+*/
+func NewDecoder[D any, H comparable](r io.Reader, reference *Odds[D, H]) (*Decoder[D, H], error) {
+	decoder := &Decoder[D, H]{
+		br:    newBinaryReader(r),
+		total: big.NewInt(0),
+	}
+
+	header := make([]byte, len(binaryMagic)+1)
+	if err := decoder.br.read(header); err != nil {
+		return nil, err
+	}
+	if string(header[:len(binaryMagic)]) != binaryMagic {
+		return nil, fmt.Errorf("%w: bad magic %q", ErrCorruptSnapshot, header[:len(binaryMagic)])
+	}
+
+	if version := header[len(binaryMagic)]; version != binaryVersion {
+		return nil, fmt.Errorf("odds: unsupported snapshot version %d", version)
+	}
+
+	typeName, err := decoder.br.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	decoder.typeName = string(typeName)
+
+	if reference == nil || reference.DecodeFunction == nil {
+		options, err := Lookup[D, H](decoder.typeName)
+		if err != nil {
+			return nil, err
+		}
+		decoder.options = options
+		decoder.hashFunction = options.HashFunction
+		decoder.decodeFunction = options.DecodeFunction
+	} else {
+		decoder.hashFunction = reference.HashFunction
+		decoder.decodeFunction = reference.DecodeFunction
+	}
+
+	return decoder, nil
+}
+
+// Type name stored in the snapshot header
+func (d *Decoder[D, H]) TypeName() string {
+	return d.typeName
+}
+
+/*
+Number of entries decoded so far. Once Next has returned io.EOF, this is the
+number of entries in the snapshot.
+*/
+func (d *Decoder[D, H]) Count() uint64 {
+	return d.read
+}
+
+// Number of bytes read from the underlying reader so far
+func (d *Decoder[D, H]) BytesRead() int64 {
+	return d.br.n
+}
+
+/*
+Decode the next entry of the snapshot. Once every entry has been read, the
+trailing count, total and checksum are validated and io.EOF is returned. Any
+other error is sticky and is returned by every following call.
+*/
+func (d *Decoder[D, H]) Next() (*Entry[D, H], error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	entry, err := d.next()
+	if err != nil {
+		d.err = err
+	}
+	return entry, err
+}
+
+func (d *Decoder[D, H]) next() (*Entry[D, H], error) {
+	more, err := d.more()
+	if err != nil {
+		return nil, err
+	}
+	if !more {
+		if err := d.finish(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	weightBytes, err := d.br.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	dataBytes, err := d.br.readBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := d.decodeFunction(dataBytes)
+	if err != nil {
+		return nil, fmt.Errorf("odds: decoding data: %w", err)
+	}
+
+	weight := new(big.Int).SetBytes(weightBytes)
+	d.read++
+	d.total.Add(d.total, weight)
+	return &Entry[D, H]{d.hashFunction(data), data, weight}, nil
+}
+
+// Reports whether another entry follows
+func (d *Decoder[D, H]) more() (bool, error) {
+	marker, err := d.br.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	switch marker {
+	case binaryEntryMarker:
+		return true, nil
+	case binaryEndMarker:
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: bad entry marker %d", ErrCorruptSnapshot, marker)
+}
+
+func (d *Decoder[D, H]) finish() error {
+	count, err := d.br.readUvarint()
+	if err != nil {
+		return err
+	}
+	if count != d.read {
+		return fmt.Errorf("%w: count %d does not match %d entries", ErrCorruptSnapshot, count, d.read)
+	}
+
+	totalBytes, err := d.br.readBytes()
+	if err != nil {
+		return err
+	}
+	if err := d.br.readChecksum(); err != nil {
+		return err
+	}
+
+	total := new(big.Int).SetBytes(totalBytes)
+	if d.total.Cmp(total) != 0 {
+		return fmt.Errorf("%w: total %s does not match sum of weights", ErrCorruptSnapshot, total)
+	}
+	return nil
+}
+
+//////////////////
+// ODDS METHODS //
+//////////////////
+
+/*
+Stream every entry of the snapshot in "r" to "callback", using "o" as the
+reference for decoding and hashing. "o" itself is not modified, which makes it
+easy to load only part of a snapshot into a different odds object. Returns the
+number of bytes read. If the callback returns an error, reading stops and that
+error is returned.
+*/
+func (o *Odds[D, H]) ReadEach(r io.Reader, callback func(*Entry[D, H]) error) (int64, error) {
+	decoder, err := NewDecoder(r, o)
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		entry, err := decoder.Next()
+		if err == io.EOF {
+			return decoder.BytesRead(), nil
+		}
+		if err != nil {
+			return decoder.BytesRead(), err
+		}
+		if err := callback(entry); err != nil {
+			return decoder.BytesRead(), err
+		}
+	}
+}
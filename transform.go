@@ -0,0 +1,192 @@
+package odds
+
+import (
+	"fmt"
+	"math/big"
+)
+
+/////////
+// MAP //
+/////////
+
+/*
+Create a new odds object of a different type by applying "mapFunction" to the
+data of every entry in "o". The new odds are created from "options", and each
+entry keeps its weight. Entries which map to the same hash have their weights
+added together, keeping the data of the first one. "o" is not modified.
+*/
+func Map[D1 any, H1 comparable, D2 any, H2 comparable](
+	o *Odds[D1, H1],
+	mapFunction func(D1) D2,
+	options *OddsOptions[D2, H2],
+) *Odds[D2, H2] {
+	mapped := options.Odds()
+	for _, entry := range o.Map {
+		mapped.Add(mapFunction(entry.Data), new(big.Int).Set(entry.Weight))
+	}
+	return mapped
+}
+
+//////////
+// BIND //
+//////////
+
+/*
+For each entry in "o", get a new group of odds of a different type from
+"bindFunction", and combine all of them into a single odds object in which each
+group takes up the same share of the weight as the entry it came from. This
+mirrors ExtendOdds, but allows the data and hash types to change.
+
+All the groups are brought to a common total using the least common multiple of
+their totals, and the result is reduced. The groups themselves are not
+modified, though their data is shared with the result. The new odds use the
+first group as their reference. Returns an error wrapping ErrZeroTotal if "o"
+has no weight or "bindFunction" returns odds with no weight.
+*/
+func Bind[D1 any, H1 comparable, D2 any, H2 comparable](
+	o *Odds[D1, H1],
+	bindFunction func(D1) *Odds[D2, H2],
+) (*Odds[D2, H2], error) {
+
+	entries := []*Entry[D1, H1]{}
+	oddsArray := []*Odds[D2, H2]{}
+	lcm := big.NewInt(1)
+
+	for _, entry := range o.Map {
+		if entry.Weight.Sign() == 0 {
+			continue
+		}
+
+		boundOdds := bindFunction(entry.Data)
+		if boundOdds.Total.Sign() == 0 {
+			return nil, fmt.Errorf("%w: Bind function returned odds with no weight", ErrZeroTotal)
+		}
+
+		entries = append(entries, entry)
+		oddsArray = append(oddsArray, boundOdds)
+
+		gcd := new(big.Int).GCD(nil, nil, lcm, boundOdds.Total)
+		lcm.Mul(lcm, new(big.Int).Quo(boundOdds.Total, gcd))
+	}
+
+	if len(oddsArray) == 0 {
+		return nil, ErrZeroTotal
+	}
+
+	bound := NewOddsFromReference(oddsArray[0])
+	for i, boundOdds := range oddsArray {
+		scaleFactor := new(big.Int).Quo(lcm, boundOdds.Total)
+		scaleFactor.Mul(scaleFactor, entries[i].Weight)
+
+		for _, entry := range boundOdds.Map {
+			bound.AddEntry(&Entry[D2, H2]{
+				entry.Hash,
+				entry.Data,
+				new(big.Int).Mul(entry.Weight, scaleFactor),
+			})
+		}
+	}
+
+	return bound.Reduce(), nil
+}
+
+/////////////
+// PRODUCT //
+/////////////
+
+// Pair of values from two different odds objects
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+/*
+Create the joint distribution of two independent odds objects of possibly
+different types. Every combination of an entry from "a" and an entry from "b"
+becomes an entry holding the pair of their data, with a weight of the product
+of their weights. The functions of the new odds are built from the functions of
+"a" and "b"; any function missing from either of them is left unset. Neither
+"a" nor "b" is modified, though their data is shared with the result.
+*/
+func Product[D1 any, H1 comparable, D2 any, H2 comparable](
+	a *Odds[D1, H1],
+	b *Odds[D2, H2],
+) *Odds[Pair[D1, D2], Pair[H1, H2]] {
+
+	// Capture the functions now so later changes to "a" or "b" do not leak in
+	hashA, hashB := a.HashFunction, b.HashFunction
+	copyA, copyB := a.CopyFunction, b.CopyFunction
+	combineA, combineB := a.CombineFunction, b.CombineFunction
+	displayA, displayB := a.DisplayFunction, b.DisplayFunction
+
+	options := NewOptions(func(p Pair[D1, D2]) Pair[H1, H2] {
+		return Pair[H1, H2]{hashA(p.First), hashB(p.Second)}
+	})
+
+	if copyA != nil && copyB != nil {
+		options.WithCopy(func(p Pair[D1, D2]) Pair[D1, D2] {
+			return Pair[D1, D2]{copyA(p.First), copyB(p.Second)}
+		})
+	}
+	if combineA != nil && combineB != nil {
+		options.WithAdd(func(p1, p2 Pair[D1, D2]) Pair[D1, D2] {
+			return Pair[D1, D2]{combineA(p1.First, p2.First), combineB(p1.Second, p2.Second)}
+		})
+	}
+	if displayA != nil && displayB != nil {
+		options.WithDisplay(func(p Pair[D1, D2]) string {
+			return "(" + displayA(p.First) + ", " + displayB(p.Second) + ")"
+		})
+	}
+
+	product := options.Odds()
+	for _, entryA := range a.Map {
+		for _, entryB := range b.Map {
+			product.AddEntry(&Entry[Pair[D1, D2], Pair[H1, H2]]{
+				Pair[H1, H2]{entryA.Hash, entryB.Hash},
+				Pair[D1, D2]{entryA.Data, entryB.Data},
+				new(big.Int).Mul(entryA.Weight, entryB.Weight),
+			})
+		}
+	}
+
+	return product
+}
+
+/////////////////
+// MARGINALIZE //
+/////////////////
+
+/*
+Collapse "o" down to the distribution of "key". Each entry of the new odds
+holds a key, which is also its hash, with the total weight of every entry of "o"
+that maps to that key. "o" is not modified.
+*/
+func Marginalize[D any, H comparable, K comparable](o *Odds[D, H], key func(D) K) *Odds[K, K] {
+	options := NewOptions(func(k K) K { return k }).WithCopy(func(k K) K { return k })
+	return Map(o, key, options)
+}
+
+/*
+Same as Marginalize, but keeps a representative data object for each key
+instead of the key itself. The new odds are hashed by "key", and the data of
+all the entries mapping to the same key are merged with o.CombineFunction. The
+other data functions of "o" are carried over to the new odds. "o" is not
+modified.
+*/
+func MarginalizeWithData[D any, H comparable, K comparable](o *Odds[D, H], key func(D) K) *Odds[D, K] {
+	marginal := NewOptions(key).
+		WithCopy(o.CopyFunction).
+		WithAdd(o.CombineFunction).
+		WithAddInPlace(o.CombineInPlaceFunction).
+		WithDisplay(o.DisplayFunction).
+		WithEncode(o.EncodeFunction).
+		WithDecode(o.DecodeFunction).
+		Odds()
+
+	for _, entry := range o.Map {
+		marginal.Add_Combine(entry.Data, new(big.Int).Set(entry.Weight))
+	}
+
+	return marginal
+}
@@ -0,0 +1,77 @@
+package odds_test
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/flywingedai/odds"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapAndBind(t *testing.T) {
+	die := test_Die(6)
+
+	parity := odds.Map(die, func(i int) string {
+		if i%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	}, odds.NewOptions(func(s string) string { return s }))
+	assert.Equal(t, 2, len(parity.Map))
+	assert.Equal(t, big.NewRat(1, 2), parity.Probability("odd"))
+
+	// Roll a die, then roll a die with that many sides
+	rolls, err := odds.Bind(test_Die(3), func(sides int) *odds.Odds[string, string] {
+		return odds.Map(test_Die(sides), strconv.Itoa, odds.NewOptions(func(s string) string { return s }))
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(11, 18), rolls.Probability("1"))
+	assert.Equal(t, big.NewRat(5, 18), rolls.Probability("2"))
+	assert.Equal(t, big.NewRat(2, 18), rolls.Probability("3"))
+	assert.Equal(t, big.NewInt(18), rolls.Total)
+
+	// Odds with no weight on either side are errors
+	_, err = odds.Bind(test_Die(3), func(sides int) *odds.Odds[int, int] { return test_Die(sides - 1) })
+	assert.ErrorIs(t, err, odds.ErrZeroTotal)
+	_, err = odds.Bind(test_Die(0), func(sides int) *odds.Odds[int, int] { return test_Die(sides) })
+	assert.ErrorIs(t, err, odds.ErrZeroTotal)
+}
+
+func TestProduct(t *testing.T) {
+	coin := odds.NewOptions(func(s string) string { return s }).Odds()
+	coin.Add("heads", big.NewInt(1))
+	coin.Add("tails", big.NewInt(1))
+
+	joint := odds.Product(test_Die(6), coin)
+	assert.Equal(t, 12, len(joint.Map))
+	assert.Equal(t, big.NewInt(12), joint.Total)
+	assert.Equal(t, big.NewRat(1, 12), joint.Probability(odds.Pair[int, string]{First: 3, Second: "tails"}))
+	assert.Equal(t, "(3, tails)", joint.DisplayFunction(odds.Pair[int, string]{First: 3, Second: "tails"}))
+
+	// Later changes to the inputs do not change the joint odds
+	coin.WithHash(func(s string) string { return "coin" })
+	assert.Equal(t, "tails", joint.HashFunction(odds.Pair[int, string]{First: 3, Second: "tails"}).Second)
+}
+
+func TestMarginalize(t *testing.T) {
+	joint := odds.Product(test_Die(6), test_Die(6))
+	sum := func(p odds.Pair[int, int]) int { return p.First + p.Second }
+
+	sums := odds.Marginalize(joint, sum)
+	assert.Equal(t, 11, len(sums.Map))
+	assert.Equal(t, big.NewRat(1, 6), sums.Probability(7))
+	assert.Equal(t, big.NewRat(1, 36), sums.Probability(12))
+
+	// Keep the highest first die seen for each sum
+	joint.WithAdd(func(p1, p2 odds.Pair[int, int]) odds.Pair[int, int] {
+		if p2.First > p1.First {
+			return p2
+		}
+		return p1
+	})
+	representatives := odds.MarginalizeWithData(joint, sum)
+	assert.Equal(t, big.NewRat(1, 6), representatives.ProbabilityOfHash(7))
+	assert.Equal(t, 6, representatives.Map[7].Data.First)
+	assert.Equal(t, 3, representatives.Map[4].Data.First)
+}